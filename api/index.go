@@ -0,0 +1,231 @@
+package api
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"mod-installer/models"
+)
+
+// IndexFileName est le nom de l'index généré à la racine de mods-meta
+const IndexFileName = "index.json"
+
+// CatalogIndex regroupe tous les fichiers de mods-meta en un seul document. Hash est
+// le SHA-256 des entrées, qui permet de vérifier que l'index n'est pas tronqué.
+type CatalogIndex struct {
+	SchemaVersion int          `json:"schema_version"`
+	GeneratedAt   time.Time    `json:"generated_at"`
+	Hash          string       `json:"hash"`
+	Mods          []IndexEntry `json:"mods"`
+}
+
+// IndexEntry est un fichier de mods-meta : son chemin et son contenu tel quel
+type IndexEntry struct {
+	Path string          `json:"path"`
+	Meta json.RawMessage `json:"meta"`
+}
+
+// ComputeHash calcule le hash des entrées, dans l'ordre de l'index. Le JSON de chaque
+// entrée est compacté au préalable : l'indentation du fichier ne change pas le hash.
+func (idx *CatalogIndex) ComputeHash() string {
+	hasher := sha256.New()
+	for _, entry := range idx.Mods {
+		var compact bytes.Buffer
+		if err := json.Compact(&compact, entry.Meta); err != nil {
+			compact.Reset()
+			compact.Write(entry.Meta)
+		}
+		hasher.Write([]byte(entry.Path))
+		hasher.Write([]byte{0})
+		hasher.Write(compact.Bytes())
+		hasher.Write([]byte{0})
+	}
+	return fmt.Sprintf("%x", hasher.Sum(nil))
+}
+
+// ToMods convertit l'index en mods, avec la même complétion d'après le chemin que
+// le parcours de l'arbre
+func (idx *CatalogIndex) ToMods() (map[string]models.Mod, error) {
+	if idx.Hash != idx.ComputeHash() {
+		return nil, fmt.Errorf("hash de l'index invalide")
+	}
+
+	mods := make(map[string]models.Mod, len(idx.Mods))
+	for _, entry := range idx.Mods {
+		meta, err := ParseModMeta(entry.Meta)
+		if err != nil {
+			fmt.Printf("Erreur lors du chargement du mod %s: %v\n", entry.Path, err)
+			continue
+		}
+		modKey, meta := completeModMeta(entry.Path, meta)
+		mods[modKey] = meta
+	}
+	if len(mods) == 0 {
+		return nil, fmt.Errorf("aucun mod trouvé")
+	}
+	return mods, nil
+}
+
+// BuildCatalogIndex génère l'index d'une copie locale de mods-meta. Chaque fichier
+// doit être décodable ; l'erreur indique le fichier fautif.
+func BuildCatalogIndex(root string) (*CatalogIndex, error) {
+	index := &CatalogIndex{SchemaVersion: SchemaVersionCurrent, GeneratedAt: time.Now().UTC()}
+
+	err := walkMetaFiles(root, func(rel string, data []byte) error {
+		if _, err := ParseModMeta(data); err != nil {
+			return FileError{Path: rel, Err: err}
+		}
+
+		var compact bytes.Buffer
+		if err := json.Compact(&compact, data); err != nil {
+			return FileError{Path: rel, Err: err}
+		}
+		index.Mods = append(index.Mods, IndexEntry{Path: rel, Meta: compact.Bytes()})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(index.Mods, func(i, j int) bool { return index.Mods[i].Path < index.Mods[j].Path })
+	index.Hash = index.ComputeHash()
+	return index, nil
+}
+
+// Catalog charge mods-meta depuis son index publié, validé par ETag, et se rabat sur
+// le parcours de l'arbre GitHub si l'index est absent ou invalide
+type Catalog struct {
+	name       string
+	client     *http.Client
+	indexURL   string
+	treeURL    string
+	rawBaseURL string
+
+	mu   sync.Mutex
+	etag string
+	mods map[string]models.Mod
+}
+
+// NewCatalog retourne le catalogue public : son index, ou à défaut l'arbre du dépôt
+func NewCatalog(indexURL string) *Catalog {
+	catalog := NewGitHubCatalog("awambst/mods-meta", "main")
+	catalog.indexURL = indexURL
+	return catalog
+}
+
+// NewGitHubCatalog lit un dépôt GitHub au format mods-meta (repo = "propriétaire/dépôt")
+func NewGitHubCatalog(repo, branch string) *Catalog {
+	if branch == "" {
+		branch = "main"
+	}
+	rawBaseURL := fmt.Sprintf("https://raw.githubusercontent.com/%s/%s/", repo, branch)
+	return &Catalog{
+		name:       repo,
+		client:     &http.Client{Timeout: 30 * time.Second},
+		indexURL:   rawBaseURL + IndexFileName,
+		treeURL:    fmt.Sprintf("https://api.github.com/repos/%s/git/trees/%s?recursive=1", repo, branch),
+		rawBaseURL: rawBaseURL,
+	}
+}
+
+// NewIndexCatalog lit uniquement un index.json servi en HTTP, sans arbre de repli
+func NewIndexCatalog(indexURL string) *Catalog {
+	return &Catalog{
+		name:     indexURL,
+		client:   &http.Client{Timeout: 30 * time.Second},
+		indexURL: indexURL,
+	}
+}
+
+// Name retourne le nom de la source, pour les journaux
+func (c *Catalog) Name() string {
+	return c.name
+}
+
+// SetTimeout change le délai maximal des requêtes du catalogue
+func (c *Catalog) SetTimeout(timeout time.Duration) {
+	c.client.Timeout = timeout
+}
+
+// SetTransport remplace le transport HTTP du catalogue (proxy, tests hors ligne)
+func (c *Catalog) SetTransport(transport http.RoundTripper) {
+	c.client.Transport = transport
+}
+
+// FetchAll retourne tous les mods du catalogue
+func (c *Catalog) FetchAll() (map[string]models.Mod, error) {
+	if c.indexURL != "" {
+		mods, err := c.fetchIndex()
+		if err == nil || c.treeURL == "" {
+			return mods, err
+		}
+		fmt.Printf("Index du catalogue indisponible (%v), parcours de l'arbre GitHub\n", err)
+	}
+	return c.fetchTree()
+}
+
+// fetchIndex télécharge l'index, ou réutilise le dernier chargé si le serveur répond
+// 304 Not Modified à If-None-Match
+func (c *Catalog) fetchIndex() (map[string]models.Mod, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	req, err := http.NewRequest("GET", c.indexURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	if c.etag != "" && c.mods != nil {
+		req.Header.Set("If-None-Match", c.etag)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusNotModified:
+		fmt.Printf("Index du catalogue inchangé (%s)\n", c.etag)
+		return copyMods(c.mods), nil
+	case http.StatusOK:
+	default:
+		return nil, fmt.Errorf("erreur HTTP %d pour l'index", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	var index CatalogIndex
+	if err := json.Unmarshal(data, &index); err != nil {
+		return nil, fmt.Errorf("index invalide: %w", err)
+	}
+	if index.SchemaVersion > SchemaVersionCurrent {
+		return nil, fmt.Errorf("version de schéma de l'index non supportée: %d", index.SchemaVersion)
+	}
+	mods, err := index.ToMods()
+	if err != nil {
+		return nil, err
+	}
+
+	fmt.Printf("Index du catalogue chargé: %d mods\n", len(mods))
+	c.etag = resp.Header.Get("ETag")
+	c.mods = mods
+	return copyMods(mods), nil
+}
+
+func copyMods(mods map[string]models.Mod) map[string]models.Mod {
+	copied := make(map[string]models.Mod, len(mods))
+	for key, mod := range mods {
+		copied[key] = mod
+	}
+	return copied
+}
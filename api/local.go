@@ -0,0 +1,131 @@
+package api
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"mod-installer/models"
+)
+
+// FileError est une erreur de chargement d'un fichier du catalogue
+type FileError struct {
+	Path string // Chemin relatif à la racine du catalogue, séparé par des "/"
+	Err  error
+}
+
+func (e FileError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Path, e.Err)
+}
+
+func (e FileError) Unwrap() error {
+	return e.Err
+}
+
+// LocalCatalog lit une copie locale de mods-meta (<jeu>/<mod>/<version>.json), avec
+// les mêmes règles de complétion de l'ID, du nom et de la version que FetchAllMods.
+// Les fichiers invalides sont ignorés et listés par LoadErrors, pour que les auteurs
+// puissent tester leurs fichiers avant de les publier.
+type LocalCatalog struct {
+	root string
+
+	mu     sync.Mutex
+	errors []FileError
+}
+
+func NewLocalCatalog(root string) *LocalCatalog {
+	return &LocalCatalog{root: root}
+}
+
+// Name retourne le dossier du catalogue
+func (lc *LocalCatalog) Name() string {
+	return lc.root
+}
+
+// FetchAll lit tous les fichiers du dossier
+func (lc *LocalCatalog) FetchAll() (map[string]models.Mod, error) {
+	if info, err := os.Stat(lc.root); err != nil || !info.IsDir() {
+		return nil, fmt.Errorf("dossier de catalogue introuvable: %s", lc.root)
+	}
+
+	mods := make(map[string]models.Mod)
+	loadErrors := make([]FileError, 0)
+	err := walkMetaFiles(lc.root, func(rel string, data []byte) error {
+		meta, err := ParseModMeta(data)
+		if err != nil {
+			fmt.Printf("Erreur lors du chargement du mod %s: %v\n", rel, err)
+			loadErrors = append(loadErrors, FileError{Path: rel, Err: err})
+			return nil
+		}
+		modKey, meta := completeModMeta(rel, meta)
+		mods[modKey] = meta
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	lc.mu.Lock()
+	lc.errors = loadErrors
+	lc.mu.Unlock()
+
+	fmt.Printf("Catalogue local %s: %d mods, %d fichier(s) en erreur\n", lc.root, len(mods), len(loadErrors))
+	if len(mods) == 0 {
+		return nil, fmt.Errorf("aucun mod trouvé dans %s", lc.root)
+	}
+	return mods, nil
+}
+
+// LoadErrors retourne les fichiers ignorés lors du dernier FetchAll
+func (lc *LocalCatalog) LoadErrors() []FileError {
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+	return append([]FileError(nil), lc.errors...)
+}
+
+// walkMetaFiles appelle fn pour chaque fichier de métadonnées d'un dossier mods-meta,
+// dans l'ordre des chemins. Les dossiers cachés (.git...) et les fichiers à la racine
+// (index.json...) sont ignorés, comme dans le parcours de l'arbre GitHub.
+func walkMetaFiles(root string, fn func(rel string, data []byte) error) error {
+	paths := make([]string, 0)
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != root && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if strings.HasSuffix(rel, ".json") && strings.Contains(rel, "/") {
+			paths = append(paths, rel)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	sort.Strings(paths)
+	for _, rel := range paths {
+		data, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(rel)))
+		if err != nil {
+			return err
+		}
+		if err := fn(rel, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"io"
 	"net/http"
-	"strconv"
 	"strings"
 	"time"
 
@@ -19,10 +18,26 @@ type GitHubTreeResponse struct {
 	} `json:"tree"`
 }
 
-func FetchAllModMeta() (map[string]models.Mod, error) {
-	treeURL := "https://api.github.com/repos/awambst/mods-meta/git/trees/main?recursive=1"
+// FetchAllModMeta charge le catalogue des sources configurées et regroupe les versions
+// de chaque mod par jeu : mods["ntw"]["fcn"] contient toutes les versions de FCN
+func FetchAllModMeta() (models.ModCatalog, error) {
+	mods, err := FetchAllMods()
+	if err != nil {
+		return nil, err
+	}
+	return models.GroupMods(mods), nil
+}
 
-	resp, err := http.Get(treeURL)
+// FetchAllMods charge le catalogue des sources configurées (ConfigureCatalog), par
+// défaut mods-meta : l'index généré s'il est publié, sinon chaque fichier du dépôt.
+// Les mods sont indexés par leur clé à plat (ntw_fcn_8.2.0).
+func FetchAllMods() (map[string]models.Mod, error) {
+	return currentCatalog().FetchAll()
+}
+
+// fetchTree parcourt l'arbre GitHub et télécharge chaque fichier de métadonnées
+func (c *Catalog) fetchTree() (map[string]models.Mod, error) {
+	resp, err := c.client.Get(c.treeURL)
 	if err != nil {
 		return nil, fmt.Errorf("erreur lors de la récupération de l'arbre GitHub: %w", err)
 	}
@@ -45,50 +60,22 @@ func FetchAllModMeta() (map[string]models.Mod, error) {
 		fmt.Printf("Fichier trouvé: %s (type: %s)\n", item.Path, item.Type)
 		
 		if strings.HasSuffix(item.Path, ".json") && item.Type == "blob" {
-			parts := strings.Split(item.Path, "/")
-			fmt.Printf("Parties du chemin: %v\n", parts)
-			
-			if len(parts) < 2 {
+			if len(strings.Split(item.Path, "/")) < 2 {
 				fmt.Printf("Chemin trop court, ignoré: %s\n", item.Path)
 				continue // structure incorrecte, ignorer
 			}
-			
-			// Construire une clé unique pour le mod basée sur le chemin complet
-			// En excluant l'extension .json
-			pathWithoutExt := strings.TrimSuffix(item.Path, ".json")
-			modKey := strings.ReplaceAll(pathWithoutExt, "/", "_")
 
-			url := fmt.Sprintf("https://raw.githubusercontent.com/awambst/mods-meta/main/%s", item.Path)
-			fmt.Printf("Tentative de chargement du mod: %s depuis %s\n", modKey, url)
+			url := c.rawBaseURL + item.Path
+			fmt.Printf("Tentative de chargement du mod: %s depuis %s\n", item.Path, url)
 			
-			meta, err := fetchOneModMeta(url)
+			meta, err := c.fetchOneModMeta(url)
 			if err != nil {
 				// Log l'erreur mais continue avec les autres mods
-				fmt.Printf("Erreur lors du chargement du mod %s: %v\n", modKey, err)
+				fmt.Printf("Erreur lors du chargement du mod %s: %v\n", item.Path, err)
 				continue
 			}
 
-			// Enrichir les métadonnées basées sur le chemin
-			if meta.ID == "" {
-				meta.ID = modKey
-			}
-			if meta.Name == "" {
-				// Utiliser le nom du dossier parent comme nom
-				if len(parts) >= 2 {
-					meta.Name = strings.ToUpper(parts[1]) // "fcn" -> "FCN"
-				} else {
-					meta.Name = "Mod sans nom"
-				}
-			}
-			if meta.Version == "" {
-				// Utiliser le nom du fichier (sans .json) comme version
-				filename := parts[len(parts)-1]
-				meta.Version = strings.TrimSuffix(filename, ".json") // "8.2.0.json" -> "8.2.0"
-			}
-			if meta.Description == "" {
-				meta.Description = fmt.Sprintf("Mod %s pour %s", meta.Name, strings.ToUpper(parts[0]))
-			}
-
+			modKey, meta := completeModMeta(item.Path, meta)
 			mods[modKey] = meta
 			fmt.Printf("Mod chargé avec succès: %s (%s)\n", modKey, meta.Name)
 		}
@@ -103,20 +90,49 @@ func FetchAllModMeta() (map[string]models.Mod, error) {
 	return mods, nil
 }
 
-// Structure pour le format JSON de votre repository
-type ModMetaFormat struct {
-	Metadata struct {
-		Link string `json:"link"`
-		Size string `json:"size"`
-		Day  string `json:"day"`
-	} `json:"metadata"`
-	Installation []string `json:"installation"`
+// completeModMeta complète les métadonnées d'après le chemin du fichier dans mods-meta
+// (<jeu>/<mod>/<version>.json) et retourne la clé du mod
+func completeModMeta(filePath string, meta models.Mod) (string, models.Mod) {
+	parts := strings.Split(filePath, "/")
+
+	// Construire une clé unique pour le mod basée sur le chemin complet
+	// En excluant l'extension .json
+	pathWithoutExt := strings.TrimSuffix(filePath, ".json")
+	modKey := strings.ReplaceAll(pathWithoutExt, "/", "_")
+
+	// Enrichir les métadonnées basées sur le chemin
+	if meta.ID == "" {
+		meta.ID = modKey
+	}
+	if meta.Name == "" {
+		// Utiliser le nom du dossier parent comme nom
+		if len(parts) >= 2 {
+			meta.Name = strings.ToUpper(parts[1]) // "fcn" -> "FCN"
+		} else {
+			meta.Name = "Mod sans nom"
+		}
+	}
+	if meta.Version == "" {
+		// Utiliser le nom du fichier (sans .json) comme version
+		filename := parts[len(parts)-1]
+		meta.Version = strings.TrimSuffix(filename, ".json") // "8.2.0.json" -> "8.2.0"
+	}
+	if len(parts) >= 2 {
+		meta.Game = parts[0]
+	}
+	if len(parts) >= 3 {
+		meta.Slug = parts[1]
+	}
+	if meta.Description == "" {
+		meta.Description = fmt.Sprintf("Mod %s pour %s", meta.Name, strings.ToUpper(parts[0]))
+	}
+	return modKey, meta
 }
 
-func fetchOneModMeta(url string) (models.Mod, error) {
+func (c *Catalog) fetchOneModMeta(url string) (models.Mod, error) {
 	fmt.Printf("Téléchargement de: %s\n", url)
 	
-	resp, err := http.Get(url)
+	resp, err := c.client.Get(url)
 	if err != nil {
 		return models.Mod{}, fmt.Errorf("erreur lors de la requête HTTP: %w", err)
 	}
@@ -133,27 +149,7 @@ func fetchOneModMeta(url string) (models.Mod, error) {
 
 	fmt.Printf("Contenu JSON reçu: %s\n", string(body))
 
-	// D'abord essayer de décoder dans le format de votre repository
-	var metaFormat ModMetaFormat
-	if err := json.Unmarshal(body, &metaFormat); err != nil {
-		return models.Mod{}, fmt.Errorf("erreur lors du décodage JSON du mod: %w", err)
-	}
-
-	// Convertir vers le format models.Mod
-	mod := models.Mod{
-		DownloadURL: metaFormat.Metadata.Link,
-		CreatedAt:   parseDate(metaFormat.Metadata.Day),
-	}
-
-	// Convertir la taille (string vers int64)
-	if sizeStr := metaFormat.Metadata.Size; sizeStr != "" {
-		if size, err := strconv.ParseInt(sizeStr, 10, 64); err == nil {
-			// Supposer que la taille est en MB, convertir en bytes
-			mod.FileSize = size * 1024 * 1024
-		}
-	}
-
-	return mod, nil
+	return ParseModMeta(body)
 }
 
 // Fonction utilitaire pour parser la date
@@ -0,0 +1,202 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"mod-installer/models"
+)
+
+// Versions du format des fichiers de mods-meta. La version 1 (sans schema_version)
+// ne décrit que le lien, la taille et la date ; la version 2 couvre tous les champs
+// de models.Mod et des règles d'installation structurées.
+const (
+	SchemaVersionLegacy  = 1
+	SchemaVersionCurrent = 2
+)
+
+// Structure pour le format JSON de votre repository
+type ModMetaFormat struct {
+	SchemaVersion int      `json:"schema_version,omitempty"`
+	ID            string   `json:"id,omitempty"`
+	Name          string   `json:"name,omitempty"`
+	Version       string   `json:"version,omitempty"`
+	Description   string   `json:"description,omitempty"`
+	Author        string   `json:"author,omitempty"`
+	Category      string   `json:"category,omitempty"`
+	Tags          []string `json:"tags,omitempty"`
+
+	Metadata struct {
+		Link     string       `json:"link"`
+		Size     SizeMB       `json:"size"`
+		Day      string       `json:"day"`
+		Updated  string       `json:"updated,omitempty"`
+		Checksum string       `json:"checksum,omitempty"`
+		Mirrors  []MirrorMeta `json:"mirrors,omitempty"`
+	} `json:"metadata"`
+
+	InstallPath  string             `json:"install_path,omitempty"`
+	Dependencies []string           `json:"dependencies,omitempty"`
+	Conflicts    []string           `json:"conflicts,omitempty"`
+	Installation []InstallDirective `json:"installation"`
+	Priority     int                `json:"priority,omitempty"` // Ordre de chargement par défaut
+}
+
+// SizeMB est une taille en mégaoctets, écrite "120" (version 1) ou 120
+type SizeMB string
+
+func (s *SizeMB) UnmarshalJSON(data []byte) error {
+	var number json.Number
+	if err := json.Unmarshal(data, &number); err == nil {
+		*s = SizeMB(number)
+		return nil
+	}
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		return fmt.Errorf("taille invalide: %s", data)
+	}
+	*s = SizeMB(strings.TrimSpace(text))
+	return nil
+}
+
+// Bytes convertit la taille en octets
+func (s SizeMB) Bytes() (int64, error) {
+	size, err := strconv.ParseInt(string(s), 10, 64)
+	if err != nil || size < 0 {
+		return 0, fmt.Errorf("taille invalide: %q (nombre entier de Mo attendu)", string(s))
+	}
+	return size * 1024 * 1024, nil
+}
+
+// MirrorMeta est un miroir de téléchargement, écrit soit comme une simple URL, soit
+// comme un objet {"link": ..., "checksum": ...}
+type MirrorMeta struct {
+	Link     string `json:"link"`
+	Checksum string `json:"checksum"`
+}
+
+func (m *MirrorMeta) UnmarshalJSON(data []byte) error {
+	var link string
+	if err := json.Unmarshal(data, &link); err == nil {
+		m.Link = link
+		return nil
+	}
+	type plain MirrorMeta
+	return json.Unmarshal(data, (*plain)(m))
+}
+
+// InstallDirective est une règle d'installation, écrite comme un objet
+// {"from": "MonMod/data", "to": "data"} ou, en version 1, comme "MonMod/data -> data"
+type InstallDirective struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+
+	text string // Forme texte d'origine
+}
+
+func (d *InstallDirective) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		d.text = text
+		if from, to, ok := strings.Cut(text, "->"); ok {
+			d.From = strings.TrimSpace(from)
+			d.To = strings.TrimSpace(to)
+		}
+		return nil
+	}
+	type plain InstallDirective
+	return json.Unmarshal(data, (*plain)(d))
+}
+
+// Rule valide la directive et la convertit en règle de l'installeur
+func (d InstallDirective) Rule() (models.InstallRule, error) {
+	if d.text != "" && d.To == "" {
+		return models.InstallRule{}, fmt.Errorf("directive d'installation invalide: %q (attendu \"dossier -> data|scripts\")", d.text)
+	}
+	rule := models.InstallRule{
+		From: strings.Trim(strings.ReplaceAll(strings.TrimSpace(d.From), "\\", "/"), "/"),
+		To:   strings.ToLower(strings.Trim(strings.TrimSpace(d.To), "/")),
+	}
+	if rule.To != models.InstallTargetData && rule.To != models.InstallTargetScripts {
+		return rule, fmt.Errorf("destination d'installation invalide: %q (data ou scripts)", d.To)
+	}
+	for _, part := range strings.Split(rule.From, "/") {
+		if part == ".." {
+			return rule, fmt.Errorf("chemin d'installation invalide: %q", d.From)
+		}
+	}
+	return rule, nil
+}
+
+// ParseModMeta décode un fichier de mods-meta, quelle que soit sa version de schéma,
+// en models.Mod. Les champs non renseignés (ID, nom, version) sont complétés ensuite
+// d'après le chemin du fichier.
+func ParseModMeta(data []byte) (models.Mod, error) {
+	var meta ModMetaFormat
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return models.Mod{}, fmt.Errorf("erreur lors du décodage JSON du mod: %w", err)
+	}
+	if meta.SchemaVersion > SchemaVersionCurrent {
+		return models.Mod{}, fmt.Errorf("version de schéma non supportée: %d (maximum %d)", meta.SchemaVersion, SchemaVersionCurrent)
+	}
+	return meta.ToMod()
+}
+
+// ToMod convertit l'entrée du catalogue vers le format models.Mod
+func (meta *ModMetaFormat) ToMod() (models.Mod, error) {
+	mod := models.Mod{
+		ID:           meta.ID,
+		Name:         meta.Name,
+		Version:      meta.Version,
+		Description:  meta.Description,
+		Author:       meta.Author,
+		DownloadURL:  meta.Metadata.Link,
+		Checksum:     strings.ToLower(meta.Metadata.Checksum),
+		Category:     meta.Category,
+		Tags:         meta.Tags,
+		CreatedAt:    parseDate(meta.Metadata.Day),
+		InstallPath:  meta.InstallPath,
+		Dependencies: meta.Dependencies,
+		Conflicts:    meta.Conflicts,
+		Priority:     meta.Priority,
+	}
+	mod.UpdatedAt = mod.CreatedAt
+	if meta.Metadata.Updated != "" {
+		mod.UpdatedAt = parseDate(meta.Metadata.Updated)
+	}
+
+	// Taille en Mo, convertie en octets
+	if meta.Metadata.Size != "" {
+		if size, err := meta.Metadata.Size.Bytes(); err == nil {
+			mod.FileSize = size
+		}
+	}
+
+	// Miroirs, dans l'ordre de préférence de l'auteur
+	for _, mirror := range meta.Metadata.Mirrors {
+		if mirror.Link == "" {
+			continue
+		}
+		mod.Mirrors = append(mod.Mirrors, models.Mirror{URL: mirror.Link, Checksum: strings.ToLower(mirror.Checksum)})
+	}
+	if mod.DownloadURL == "" && len(mod.Mirrors) > 0 {
+		mod.DownloadURL = mod.Mirrors[0].URL
+	}
+
+	// Les fichiers de version 1 contiennent des consignes libres : seules celles de la
+	// forme "dossier -> data|scripts" sont retenues
+	for _, directive := range meta.Installation {
+		rule, err := directive.Rule()
+		if err != nil {
+			if meta.SchemaVersion < SchemaVersionCurrent {
+				fmt.Printf("Directive d'installation ignorée: %v\n", err)
+				continue
+			}
+			return models.Mod{}, err
+		}
+		mod.Installation = append(mod.Installation, rule)
+	}
+	return mod, nil
+}
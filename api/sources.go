@@ -0,0 +1,165 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"mod-installer/config"
+	"mod-installer/models"
+)
+
+// CatalogSource est une origine de mods : dépôt GitHub, index HTTP ou dossier local
+type CatalogSource interface {
+	Name() string
+	FetchAll() (map[string]models.Mod, error)
+}
+
+// URL par défaut des anciennes configurations, qui ne désigne aucun catalogue
+const legacyRepositoryURL = "https://api.example.com/mods"
+
+var (
+	defaultMu      sync.Mutex
+	defaultCatalog CatalogSource = NewCatalog("https://raw.githubusercontent.com/awambst/mods-meta/main/" + IndexFileName)
+)
+
+// ConfigureCatalog remplace les sources utilisées par FetchAllModMeta par celles de
+// la configuration
+func ConfigureCatalog(cfg *config.Config) error {
+	catalog, err := CatalogFromConfig(cfg)
+	if err != nil {
+		return err
+	}
+	defaultMu.Lock()
+	defaultCatalog = catalog
+	defaultMu.Unlock()
+	return nil
+}
+
+func currentCatalog() CatalogSource {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	return defaultCatalog
+}
+
+// CatalogFromConfig construit le catalogue fusionné des sources configurées.
+// ModRepositoryURL, si renseignée, est un index prioritaire sur les autres sources.
+// LocalCatalogPath remplace toutes les sources pour travailler hors ligne.
+func CatalogFromConfig(cfg *config.Config) (*MultiCatalog, error) {
+	if cfg.LocalCatalogPath != "" {
+		return NewMultiCatalog(NewLocalCatalog(cfg.LocalCatalogPath)), nil
+	}
+
+	configs := make([]config.CatalogSourceConfig, 0, len(cfg.CatalogSources)+1)
+	if cfg.ModRepositoryURL != "" && cfg.ModRepositoryURL != legacyRepositoryURL {
+		configs = append(configs, config.CatalogSourceConfig{Type: config.CatalogSourceIndex, URL: cfg.ModRepositoryURL})
+	}
+	configs = append(configs, cfg.CatalogSources...)
+	if len(configs) == 0 {
+		configs = config.DefaultCatalogSources()
+	}
+
+	sources := make([]CatalogSource, 0, len(configs))
+	for _, sc := range configs {
+		source, err := NewCatalogSource(sc)
+		if err != nil {
+			return nil, err
+		}
+		if catalog, ok := source.(*Catalog); ok && cfg.APITimeout > 0 {
+			catalog.SetTimeout(time.Duration(cfg.APITimeout) * time.Second)
+		}
+		sources = append(sources, source)
+	}
+	return NewMultiCatalog(sources...), nil
+}
+
+// NewCatalogSource crée la source décrite dans la configuration
+func NewCatalogSource(sc config.CatalogSourceConfig) (CatalogSource, error) {
+	var source CatalogSource
+	switch sc.Type {
+	case config.CatalogSourceGitHub:
+		if sc.Repo == "" {
+			return nil, fmt.Errorf("source de catalogue github sans dépôt")
+		}
+		source = NewGitHubCatalog(sc.Repo, sc.Branch)
+	case config.CatalogSourceIndex:
+		if sc.URL == "" {
+			return nil, fmt.Errorf("source de catalogue index sans URL")
+		}
+		source = NewIndexCatalog(sc.URL)
+	case config.CatalogSourceLocal:
+		if sc.Path == "" {
+			return nil, fmt.Errorf("source de catalogue locale sans dossier")
+		}
+		source = NewLocalCatalog(sc.Path)
+	default:
+		return nil, fmt.Errorf("type de source de catalogue inconnu: %q", sc.Type)
+	}
+
+	if sc.Name != "" {
+		return namedSource{CatalogSource: source, name: sc.Name}, nil
+	}
+	return source, nil
+}
+
+// namedSource donne à une source le nom choisi dans la configuration
+type namedSource struct {
+	CatalogSource
+	name string
+}
+
+func (s namedSource) Name() string {
+	return s.name
+}
+
+// MultiCatalog fusionne plusieurs sources. Elles sont listées par priorité
+// décroissante : pour une même clé de mod, la première source l'emporte. Une source
+// en échec n'empêche pas de charger les autres.
+type MultiCatalog struct {
+	sources []CatalogSource
+}
+
+func NewMultiCatalog(sources ...CatalogSource) *MultiCatalog {
+	return &MultiCatalog{sources: sources}
+}
+
+func (m *MultiCatalog) Name() string {
+	return fmt.Sprintf("%d sources", len(m.sources))
+}
+
+// Sources retourne les sources fusionnées, par priorité décroissante
+func (m *MultiCatalog) Sources() []CatalogSource {
+	return m.sources
+}
+
+func (m *MultiCatalog) FetchAll() (map[string]models.Mod, error) {
+	merged := make(map[string]models.Mod)
+	origin := make(map[string]string)
+	var errs []error
+
+	for _, source := range m.sources {
+		mods, err := source.FetchAll()
+		if err != nil {
+			fmt.Printf("Source de catalogue %s indisponible: %v\n", source.Name(), err)
+			errs = append(errs, fmt.Errorf("%s: %w", source.Name(), err))
+			continue
+		}
+		for key, mod := range mods {
+			if previous, exists := origin[key]; exists {
+				fmt.Printf("Mod %s de %s masqué par %s\n", key, source.Name(), previous)
+				continue
+			}
+			merged[key] = mod
+			origin[key] = source.Name()
+		}
+	}
+
+	if len(merged) == 0 {
+		if len(errs) > 0 {
+			return nil, errors.Join(errs...)
+		}
+		return nil, fmt.Errorf("aucun mod trouvé")
+	}
+	return merged, nil
+}
@@ -0,0 +1,285 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Severity est la gravité d'un diagnostic du validateur
+type Severity int
+
+const (
+	SeverityWarning Severity = iota
+	SeverityError
+)
+
+func (s Severity) String() string {
+	if s == SeverityError {
+		return "erreur"
+	}
+	return "avertissement"
+}
+
+func (s Severity) MarshalJSON() ([]byte, error) {
+	if s == SeverityError {
+		return []byte(`"error"`), nil
+	}
+	return []byte(`"warning"`), nil
+}
+
+// Diagnostic est un problème trouvé dans un fichier du catalogue
+type Diagnostic struct {
+	Path     string   `json:"path"`            // Fichier, relatif à la racine du catalogue
+	Field    string   `json:"field,omitempty"` // Champ concerné (metadata.day, dependencies[0]...)
+	Severity Severity `json:"severity"`
+	Message  string   `json:"message"`
+}
+
+func (d Diagnostic) String() string {
+	if d.Field == "" {
+		return fmt.Sprintf("%s: %s: %s", d.Path, d.Severity, d.Message)
+	}
+	return fmt.Sprintf("%s: %s: %s: %s", d.Path, d.Field, d.Severity, d.Message)
+}
+
+// HasErrors indique si au moins un diagnostic est une erreur
+func HasErrors(diagnostics []Diagnostic) bool {
+	for _, d := range diagnostics {
+		if d.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// Extensions d'archives que l'installeur sait traiter
+var knownArchiveExtensions = []string{".zip", ".rar", ".7z", ".tar", ".tar.gz", ".tgz", ".pack"}
+
+// Extensions de pages web : le lien mène à une page d'hébergeur, pas à l'archive
+var pageExtensions = []string{".html", ".htm", ".php", ".asp", ".aspx", ".jsp"}
+
+var checksumRe = regexp.MustCompile(`^[0-9a-fA-F]{64}$`)
+
+// catalogFile est un fichier du catalogue en cours de validation
+type catalogFile struct {
+	path  string
+	meta  ModMetaFormat
+	id    string
+	group string // Mod sans la version : <jeu>_<mod>
+}
+
+// ValidateCatalog vérifie tous les fichiers d'une copie locale de mods-meta, puis les
+// références entre mods (ID en double, dépendances et conflits inconnus). L'erreur
+// retournée ne concerne que la lecture du dossier.
+func ValidateCatalog(root string) ([]Diagnostic, error) {
+	diagnostics := make([]Diagnostic, 0)
+	files := make([]catalogFile, 0)
+
+	err := walkMetaFiles(root, func(rel string, data []byte) error {
+		meta, fileDiagnostics := validateModMeta(rel, data)
+		diagnostics = append(diagnostics, fileDiagnostics...)
+		if meta != nil {
+			mod, _ := meta.ToMod()
+			_, mod = completeModMeta(rel, mod)
+			files = append(files, catalogFile{path: rel, meta: *meta, id: mod.ID, group: mod.GroupKey()})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	diagnostics = append(diagnostics, validateReferences(files)...)
+	sort.SliceStable(diagnostics, func(i, j int) bool { return diagnostics[i].Path < diagnostics[j].Path })
+	return diagnostics, nil
+}
+
+// ValidateModMeta vérifie un seul fichier de métadonnées
+func ValidateModMeta(path string, data []byte) []Diagnostic {
+	_, diagnostics := validateModMeta(path, data)
+	return diagnostics
+}
+
+func validateModMeta(filePath string, data []byte) (*ModMetaFormat, []Diagnostic) {
+	diagnostics := make([]Diagnostic, 0)
+	report := func(severity Severity, field, format string, args ...interface{}) {
+		diagnostics = append(diagnostics, Diagnostic{Path: filePath, Field: field, Severity: severity, Message: fmt.Sprintf(format, args...)})
+	}
+
+	var meta ModMetaFormat
+	if err := json.Unmarshal(data, &meta); err != nil {
+		report(SeverityError, "", "JSON invalide: %v", err)
+		return nil, diagnostics
+	}
+	if meta.SchemaVersion > SchemaVersionCurrent {
+		report(SeverityError, "schema_version", "version de schéma non supportée: %d (maximum %d)", meta.SchemaVersion, SchemaVersionCurrent)
+		return nil, diagnostics
+	}
+
+	// Champs obligatoires
+	if meta.Metadata.Link == "" && len(meta.Metadata.Mirrors) == 0 {
+		report(SeverityError, "metadata.link", "lien de téléchargement manquant")
+	}
+	if meta.Metadata.Size == "" {
+		report(SeverityError, "metadata.size", "taille manquante")
+	} else if size, err := meta.Metadata.Size.Bytes(); err != nil {
+		report(SeverityError, "metadata.size", "%v", err)
+	} else if size == 0 {
+		report(SeverityWarning, "metadata.size", "taille nulle")
+	}
+	if meta.Metadata.Day == "" {
+		report(SeverityError, "metadata.day", "date manquante")
+	} else if err := validateDate(meta.Metadata.Day); err != nil {
+		report(SeverityError, "metadata.day", "%v", err)
+	}
+	if meta.Metadata.Updated != "" {
+		if err := validateDate(meta.Metadata.Updated); err != nil {
+			report(SeverityError, "metadata.updated", "%v", err)
+		}
+	}
+
+	// Liens et checksums
+	if meta.Metadata.Link != "" {
+		for _, problem := range validateDownloadURL(meta.Metadata.Link) {
+			report(problem.severity, "metadata.link", "%s", problem.message)
+		}
+	}
+	if meta.Metadata.Checksum != "" && !checksumRe.MatchString(meta.Metadata.Checksum) {
+		report(SeverityError, "metadata.checksum", "checksum SHA-256 invalide (64 caractères hexadécimaux attendus)")
+	}
+	for i, mirror := range meta.Metadata.Mirrors {
+		field := fmt.Sprintf("metadata.mirrors[%d]", i)
+		if mirror.Link == "" {
+			report(SeverityError, field, "miroir sans lien")
+			continue
+		}
+		for _, problem := range validateDownloadURL(mirror.Link) {
+			report(problem.severity, field, "%s", problem.message)
+		}
+		if mirror.Checksum != "" && !checksumRe.MatchString(mirror.Checksum) {
+			report(SeverityError, field+".checksum", "checksum SHA-256 invalide (64 caractères hexadécimaux attendus)")
+		}
+	}
+
+	// Règles d'installation
+	for i, directive := range meta.Installation {
+		if _, err := directive.Rule(); err != nil {
+			severity := SeverityError
+			if meta.SchemaVersion < SchemaVersionCurrent {
+				severity = SeverityWarning // consignes libres de la version 1, ignorées
+			}
+			report(severity, fmt.Sprintf("installation[%d]", i), "%v", err)
+		}
+	}
+
+	return &meta, diagnostics
+}
+
+// validateDate vérifie le format MM/DD/YYYY, sans repli sur la date du jour
+func validateDate(value string) error {
+	if _, err := time.Parse("01/02/2006", value); err != nil {
+		return fmt.Errorf("date invalide: %q (format MM/DD/YYYY attendu)", value)
+	}
+	return nil
+}
+
+type urlProblem struct {
+	severity Severity
+	message  string
+}
+
+// validateDownloadURL vérifie qu'un lien ressemble à un lien téléchargeable
+func validateDownloadURL(rawURL string) []urlProblem {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return []urlProblem{{SeverityError, fmt.Sprintf("URL invalide: %v", err)}}
+	}
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return []urlProblem{{SeverityError, fmt.Sprintf("schéma non supporté: %q (http ou https)", parsed.Scheme)}}
+	}
+	host := strings.ToLower(parsed.Hostname())
+	if host == "" || !strings.Contains(host, ".") {
+		return []urlProblem{{SeverityError, fmt.Sprintf("hôte invalide: %q", host)}}
+	}
+
+	problems := make([]urlProblem, 0)
+	if host == "example.com" || strings.HasSuffix(host, ".example.com") || host == "localhost" {
+		problems = append(problems, urlProblem{SeverityWarning, fmt.Sprintf("hôte de test: %s", host)})
+	}
+	if parsed.Scheme == "http" {
+		problems = append(problems, urlProblem{SeverityWarning, "lien non chiffré (http)"})
+	}
+
+	// Seuls les liens directs ont une extension ; les pages d'hébergeurs n'en ont pas
+	name := strings.ToLower(path.Base(parsed.Path))
+	if ext := path.Ext(name); ext != "" && !isGoogleDriveHost(host) && !isKnownArchive(name) && !isPageExtension(ext) {
+		problems = append(problems, urlProblem{SeverityError, fmt.Sprintf("type d'archive inconnu: %s", ext)})
+	}
+	return problems
+}
+
+func isKnownArchive(name string) bool {
+	for _, ext := range knownArchiveExtensions {
+		if strings.HasSuffix(name, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+func isPageExtension(ext string) bool {
+	for _, page := range pageExtensions {
+		if ext == page {
+			return true
+		}
+	}
+	return false
+}
+
+func isGoogleDriveHost(host string) bool {
+	return host == "drive.google.com" || host == "docs.google.com"
+}
+
+// validateReferences détecte les ID en double et les dépendances ou conflits qui ne
+// désignent aucun mod du catalogue (ni un ID, ni un mod toutes versions confondues)
+func validateReferences(files []catalogFile) []Diagnostic {
+	diagnostics := make([]Diagnostic, 0)
+
+	byID := make(map[string][]string)
+	known := make(map[string]bool)
+	for _, file := range files {
+		byID[file.id] = append(byID[file.id], file.path)
+		known[file.id] = true
+		known[file.group] = true
+	}
+
+	for _, file := range files {
+		if others := byID[file.id]; len(others) > 1 {
+			diagnostics = append(diagnostics, Diagnostic{
+				Path: file.path, Field: "id", Severity: SeverityError,
+				Message: fmt.Sprintf("ID %q en double: %s", file.id, strings.Join(others, ", ")),
+			})
+		}
+
+		check := func(field string, refs []string) {
+			for i, ref := range refs {
+				fieldName := fmt.Sprintf("%s[%d]", field, i)
+				switch {
+				case ref == file.id || ref == file.group:
+					diagnostics = append(diagnostics, Diagnostic{Path: file.path, Field: fieldName, Severity: SeverityWarning, Message: fmt.Sprintf("le mod se référence lui-même: %q", ref)})
+				case !known[ref]:
+					diagnostics = append(diagnostics, Diagnostic{Path: file.path, Field: fieldName, Severity: SeverityError, Message: fmt.Sprintf("mod inconnu: %q", ref)})
+				}
+			}
+		}
+		check("dependencies", file.meta.Dependencies)
+		check("conflicts", file.meta.Conflicts)
+	}
+	return diagnostics
+}
@@ -0,0 +1,126 @@
+// Outils en ligne de commande pour le dépôt mods-meta
+//
+//	catalog index [-o index.json] <mods-meta>
+//	catalog validate [-json] <mods-meta>
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"mod-installer/api"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "index":
+		err = runIndex(os.Args[2:])
+	case "validate":
+		err = runValidate(os.Args[2:])
+	case "-h", "--help", "help":
+		usage()
+		return
+	default:
+		fmt.Fprintf(os.Stderr, "commande inconnue: %s\n", os.Args[1])
+		usage()
+		os.Exit(2)
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "erreur: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "Usage:")
+	fmt.Fprintln(os.Stderr, "  catalog index [-o fichier] <dossier mods-meta>   génère index.json")
+	fmt.Fprintln(os.Stderr, "  catalog validate [-json] <dossier mods-meta>     vérifie les fichiers du catalogue")
+}
+
+// runIndex génère l'index d'une copie locale de mods-meta
+func runIndex(args []string) error {
+	flags := flag.NewFlagSet("index", flag.ExitOnError)
+	output := flags.String("o", "", "fichier de sortie (par défaut <mods-meta>/index.json)")
+	flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		return fmt.Errorf("dossier mods-meta attendu")
+	}
+	root := flags.Arg(0)
+	if *output == "" {
+		*output = filepath.Join(root, api.IndexFileName)
+	}
+
+	index, err := api.BuildCatalogIndex(root)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := *output + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, *output); err != nil {
+		return err
+	}
+
+	fmt.Printf("%d mods indexés dans %s (hash %s)\n", len(index.Mods), *output, index.Hash[:12])
+	return nil
+}
+
+// runValidate affiche les diagnostics du catalogue ; le code de sortie est 1 s'il
+// contient au moins une erreur
+func runValidate(args []string) error {
+	flags := flag.NewFlagSet("validate", flag.ExitOnError)
+	asJSON := flags.Bool("json", false, "diagnostics au format JSON")
+	flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		return fmt.Errorf("dossier mods-meta attendu")
+	}
+
+	diagnostics, err := api.ValidateCatalog(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	if *asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(diagnostics); err != nil {
+			return err
+		}
+	} else {
+		for _, d := range diagnostics {
+			fmt.Println(d)
+		}
+	}
+
+	errorCount := 0
+	for _, d := range diagnostics {
+		if d.Severity == api.SeverityError {
+			errorCount++
+		}
+	}
+	if !*asJSON {
+		fmt.Printf("%d erreur(s), %d avertissement(s)\n", errorCount, len(diagnostics)-errorCount)
+	}
+	if errorCount > 0 {
+		os.Exit(1)
+	}
+	return nil
+}
@@ -18,8 +18,10 @@ type Config struct {
 	ConfigPath   string `json:"config_path"`
 	
 	// API
-	ModRepositoryURL string `json:"mod_repository_url"`
-	APITimeout       int    `json:"api_timeout"`
+	ModRepositoryURL string                `json:"mod_repository_url"` // URL d'un index de catalogue, prioritaire si renseignée
+	APITimeout       int                   `json:"api_timeout"`
+	CatalogSources   []CatalogSourceConfig `json:"catalog_sources"`
+	LocalCatalogPath string                `json:"-"` // Dossier mods-meta utilisé seul (option -catalog), non enregistré
 	
 	// Interface
 	WindowWidth  int  `json:"window_width"`
@@ -32,6 +34,31 @@ type Config struct {
 	CreateBackups          bool `json:"create_backups"`
 }
 
+// Types de sources de catalogue
+const (
+	CatalogSourceGitHub = "github" // Dépôt GitHub au format mods-meta
+	CatalogSourceIndex  = "index"  // index.json servi en HTTP
+	CatalogSourceLocal  = "local"  // Copie locale de mods-meta
+)
+
+// CatalogSourceConfig décrit une source de catalogue. Les sources sont listées par
+// priorité décroissante : pour un même mod, la première source qui le fournit l'emporte.
+type CatalogSourceConfig struct {
+	Name   string `json:"name,omitempty"`
+	Type   string `json:"type"`
+	Repo   string `json:"repo,omitempty"`   // github : "propriétaire/dépôt"
+	Branch string `json:"branch,omitempty"` // github : "main" par défaut
+	URL    string `json:"url,omitempty"`    // index : URL de index.json
+	Path   string `json:"path,omitempty"`   // local : dossier mods-meta
+}
+
+// DefaultCatalogSources retourne le catalogue public
+func DefaultCatalogSources() []CatalogSourceConfig {
+	return []CatalogSourceConfig{
+		{Name: "mods-meta", Type: CatalogSourceGitHub, Repo: "awambst/mods-meta", Branch: "main"},
+	}
+}
+
 // Default retourne une configuration par défaut
 func Default() *Config {
 	homeDir, _ := os.UserHomeDir()
@@ -64,8 +91,9 @@ func Default() *Config {
     ModsPath:               filepath.Join(base, "mods"),
 		TempPath:               filepath.Join(base, "temp"),
     ConfigPath:             filepath.Join(base, "config.json"),
-		ModRepositoryURL:       "https://api.example.com/mods",
+		ModRepositoryURL:       "",
 		APITimeout:             30,
+		CatalogSources:         DefaultCatalogSources(),
 		WindowWidth:            800,
 		WindowHeight:           600,
 		DarkTheme:              false,
@@ -118,6 +146,11 @@ func (c *Config) Save() error {
 	return os.WriteFile(c.ConfigPath, data, 0644)
 }
 
+// CacheDir retourne le dossier de cache de l'application (contient config.json)
+func (c *Config) CacheDir() string {
+	return filepath.Dir(c.ConfigPath)
+}
+
 // SetGamePath met à jour le chemin du jeu et sauvegarde
 func (c *Config) SetGamePath(path string) error {
 	c.GamePath = path
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"flag"
 	"log"
 
 	"fyne.io/fyne/v2/app"
@@ -11,11 +12,15 @@ import (
 
 
 func main() {
+	localCatalog := flag.String("catalog", "", "dossier mods-meta local à utiliser à la place des catalogues configurés")
+	flag.Parse()
+
 	// Initialiser la configuration
 	cfg, err := config.Load()
 	if err != nil {
 		log.Fatalf("Erreur lors du chargement de la configuration: %v", err)
 	}
+	cfg.LocalCatalogPath = *localCatalog
 
 	// Créer l'application Fyne
 	myApp := app.New()
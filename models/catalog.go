@@ -0,0 +1,126 @@
+// models/catalog.go
+package models
+
+import "sort"
+
+// ModCatalog regroupe les mods du catalogue par jeu puis par mod :
+// catalog["ntw"]["fcn"] contient toutes les versions de FCN
+type ModCatalog map[string]map[string]ModVersions
+
+// ModVersions contient les versions d'un mod, de la plus récente à la plus ancienne
+type ModVersions []Mod
+
+// ModGroup est un mod du catalogue avec toutes ses versions
+type ModGroup struct {
+	Game     string
+	Slug     string
+	Versions ModVersions
+}
+
+// Key retourne la clé du mod toutes versions confondues (<jeu>_<mod>)
+func (g ModGroup) Key() string {
+	return groupKey(g.Game, g.Slug)
+}
+
+// GroupMods regroupe les versions d'un catalogue à plat (clé ntw_fcn_8.2.0). Un mod
+// sans emplacement dans le catalogue forme un groupe à lui seul.
+func GroupMods(mods map[string]Mod) ModCatalog {
+	catalog := make(ModCatalog)
+	for _, mod := range mods {
+		slug := mod.Slug
+		if slug == "" {
+			slug = mod.ID
+		}
+		if catalog[mod.Game] == nil {
+			catalog[mod.Game] = make(map[string]ModVersions)
+		}
+		catalog[mod.Game][slug] = append(catalog[mod.Game][slug], mod)
+	}
+	for _, byMod := range catalog {
+		for slug, versions := range byMod {
+			versions.sort()
+			byMod[slug] = versions
+		}
+	}
+	return catalog
+}
+
+// Groups retourne les mods du catalogue triés par jeu puis par mod
+func (c ModCatalog) Groups() []ModGroup {
+	groups := make([]ModGroup, 0)
+	for game, byMod := range c {
+		for slug, versions := range byMod {
+			groups = append(groups, ModGroup{Game: game, Slug: slug, Versions: versions})
+		}
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		if groups[i].Game != groups[j].Game {
+			return groups[i].Game < groups[j].Game
+		}
+		return groups[i].Slug < groups[j].Slug
+	})
+	return groups
+}
+
+// Find retourne le mod d'ID donné, quelle que soit sa version
+func (c ModCatalog) Find(id string) *Mod {
+	for _, byMod := range c {
+		for _, versions := range byMod {
+			for i := range versions {
+				if versions[i].ID == id {
+					return &versions[i]
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (v ModVersions) sort() {
+	sort.SliceStable(v, func(i, j int) bool {
+		if c := CompareVersions(v[i].Version, v[j].Version); c != 0 {
+			return c > 0
+		}
+		return v[i].ID < v[j].ID
+	})
+}
+
+// Latest retourne la version la plus récente, en préférant une version finale à une
+// préversion plus récente
+func (v ModVersions) Latest() *Mod {
+	for i := range v {
+		if !IsPrerelease(v[i].Version) {
+			return &v[i]
+		}
+	}
+	if len(v) > 0 {
+		return &v[0]
+	}
+	return nil
+}
+
+// Find retourne la version demandée
+func (v ModVersions) Find(version string) *Mod {
+	for i := range v {
+		if v[i].Version == version {
+			return &v[i]
+		}
+	}
+	return nil
+}
+
+// Versions retourne les numéros de version, du plus récent au plus ancien
+func (v ModVersions) Versions() []string {
+	versions := make([]string, len(v))
+	for i, mod := range v {
+		versions[i] = mod.Version
+	}
+	return versions
+}
+
+func groupKey(game, slug string) string {
+	if game == "" {
+		return slug
+	}
+	return game + "_" + slug
+}
@@ -0,0 +1,185 @@
+// models/manifest.go
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"mod-installer/utils"
+)
+
+// InstalledFile décrit un fichier écrit par l'installation d'un mod
+type InstalledFile struct {
+	Root     string    `json:"root"` // Dossier racine de destination (data ou scripts)
+	Path     string    `json:"path"` // Chemin relatif à Root
+	Size     int64     `json:"size"`
+	SHA256   string    `json:"sha256"`
+	ModTime  time.Time `json:"mod_time"`
+	Replaced bool      `json:"replaced"` // Le fichier existait avant l'installation
+}
+
+// FullPath retourne le chemin absolu du fichier installé
+func (f *InstalledFile) FullPath() string {
+	return filepath.Join(f.Root, f.Path)
+}
+
+// InstallManifest est l'enregistrement persistant d'une installation de mod
+type InstallManifest struct {
+	ModID       string          `json:"mod_id"`
+	Group       string          `json:"group,omitempty"` // Mod toutes versions confondues (Mod.GroupKey)
+	Name        string          `json:"name"`
+	Version     string          `json:"version"`
+	GamePath    string          `json:"game_path"`
+	ArchivePath string          `json:"archive_path"`
+	InstalledAt time.Time       `json:"installed_at"`
+	BackupID    string          `json:"backup_id,omitempty"` // Jeu de sauvegardes des fichiers écrasés
+	Files       []InstalledFile `json:"files"`
+}
+
+// InstallState représente l'état d'un mod sur le disque
+type InstallState int
+
+const (
+	InstallStateNone InstallState = iota
+	InstallStateComplete
+	InstallStatePartial
+	InstallStateModified
+)
+
+func (s InstallState) String() string {
+	switch s {
+	case InstallStateNone:
+		return "Non installé"
+	case InstallStateComplete:
+		return "Installé"
+	case InstallStatePartial:
+		return "Installation incomplète"
+	case InstallStateModified:
+		return "Fichiers modifiés"
+	default:
+		return "Inconnu"
+	}
+}
+
+// ManifestCheck est le résultat de la vérification d'un manifeste sur le disque
+type ManifestCheck struct {
+	State    InstallState
+	Missing  []string
+	Modified []string
+}
+
+// ManifestFileName retourne le nom du fichier de manifeste pour un mod
+func ManifestFileName(modID string) string {
+	safeID := strings.NewReplacer("/", "_", "\\", "_", ":", "_").Replace(modID)
+	return safeID + ".json"
+}
+
+// LoadInstallManifest charge le manifeste d'un mod. Retourne (nil, nil) si le mod
+// n'a jamais été installé.
+func LoadInstallManifest(manifestDir, modID string) (*InstallManifest, error) {
+	data, err := os.ReadFile(filepath.Join(manifestDir, ManifestFileName(modID)))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erreur lecture manifeste %s: %w", modID, err)
+	}
+
+	var manifest InstallManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("manifeste %s corrompu: %w", modID, err)
+	}
+	return &manifest, nil
+}
+
+// ListInstallManifests charge tous les manifestes présents dans manifestDir
+func ListInstallManifests(manifestDir string) ([]*InstallManifest, error) {
+	matches, err := filepath.Glob(filepath.Join(manifestDir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	manifests := make([]*InstallManifest, 0, len(matches))
+	for _, match := range matches {
+		data, err := os.ReadFile(match)
+		if err != nil {
+			return nil, fmt.Errorf("erreur lecture manifeste %s: %w", match, err)
+		}
+
+		var manifest InstallManifest
+		if err := json.Unmarshal(data, &manifest); err != nil {
+			fmt.Printf("Manifeste ignoré (corrompu): %s\n", match)
+			continue
+		}
+		manifests = append(manifests, &manifest)
+	}
+	return manifests, nil
+}
+
+// RemoveInstallManifest supprime le manifeste d'un mod
+func RemoveInstallManifest(manifestDir, modID string) error {
+	err := os.Remove(filepath.Join(manifestDir, ManifestFileName(modID)))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+// Save enregistre le manifeste dans manifestDir
+func (m *InstallManifest) Save(manifestDir string) error {
+	if err := os.MkdirAll(manifestDir, 0755); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// Écriture via un fichier temporaire pour ne jamais laisser un manifeste tronqué
+	path := filepath.Join(manifestDir, ManifestFileName(m.ModID))
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
+
+// Check compare les fichiers enregistrés avec le disque. Le hash n'est recalculé
+// que lorsque la date de modification ne correspond plus.
+func (m *InstallManifest) Check() ManifestCheck {
+	check := ManifestCheck{State: InstallStateComplete}
+
+	for _, file := range m.Files {
+		info, err := os.Stat(file.FullPath())
+		if err != nil {
+			check.Missing = append(check.Missing, file.FullPath())
+			continue
+		}
+
+		if info.Size() != file.Size {
+			check.Modified = append(check.Modified, file.FullPath())
+			continue
+		}
+
+		if !info.ModTime().Equal(file.ModTime) {
+			if sum, err := utils.CalculateSHA256(file.FullPath()); err != nil || sum != file.SHA256 {
+				check.Modified = append(check.Modified, file.FullPath())
+			}
+		}
+	}
+
+	switch {
+	case len(m.Files) > 0 && len(check.Missing) == len(m.Files):
+		check.State = InstallStateNone
+	case len(check.Missing) > 0:
+		check.State = InstallStatePartial
+	case len(check.Modified) > 0:
+		check.State = InstallStateModified
+	}
+	return check
+}
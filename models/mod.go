@@ -1,7 +1,10 @@
 // models/mod.go
 package models
 
-import "time"
+import (
+	"path/filepath"
+	"time"
+)
 
 // Mod représente un mod disponible à l'installation
 type Mod struct {
@@ -13,21 +16,93 @@ type Mod struct {
 	DownloadURL string    `json:"download_url"`
 	FileSize    int64     `json:"file_size"`
 	Checksum    string    `json:"checksum"`
+	Mirrors     []Mirror  `json:"mirrors,omitempty"`
 	Category    string    `json:"category"`
 	Tags        []string  `json:"tags"`
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
 	
+	// Emplacement dans le catalogue (<jeu>/<mod>/<version>.json)
+	Game string `json:"game,omitempty"`
+	Slug string `json:"slug,omitempty"` // Dossier du mod, commun à toutes ses versions
+	
 	// Métadonnées d'installation
-	InstallPath  string   `json:"install_path"`
-	Dependencies []string `json:"dependencies"`
-	Conflicts    []string `json:"conflicts"`
+	InstallPath  string        `json:"install_path"`
+	Dependencies []string      `json:"dependencies"`
+	Conflicts    []string      `json:"conflicts"`
+	Installation []InstallRule `json:"installation,omitempty"`
+	Priority     int           `json:"priority,omitempty"` // Ordre de chargement par défaut : plus haut, installé plus tard
+}
+
+// IsInstalled vérifie si le mod est installé intégralement dans gamePath,
+// d'après son manifeste enregistré dans manifestDir
+func (m *Mod) IsInstalled(manifestDir, gamePath string) bool {
+	manifest, err := LoadInstallManifest(manifestDir, m.ID)
+	if err != nil || manifest == nil {
+		return false
+	}
+	if filepath.Clean(manifest.GamePath) != filepath.Clean(gamePath) {
+		return false
+	}
+	return manifest.Check().State == InstallStateComplete
+}
+
+// GroupKey retourne la clé du mod toutes versions confondues (<jeu>_<mod>), celle
+// que les dépendances et les conflits peuvent désigner
+func (m *Mod) GroupKey() string {
+	if m.Slug == "" {
+		return groupKey(m.Game, m.ID)
+	}
+	return groupKey(m.Game, m.Slug)
 }
 
-// IsInstalled vérifie si le mod est déjà installé
-func (m *Mod) IsInstalled(gamePath string) bool {
-	// Logique pour vérifier l'installation
-	return false // TODO: implémenter
+// Mirror est un emplacement de téléchargement du mod. Checksum, s'il est renseigné,
+// remplace celui du mod pour ce miroir (archive reconditionnée par l'hébergeur).
+type Mirror struct {
+	URL      string `json:"url"`
+	Checksum string `json:"checksum,omitempty"`
+}
+
+// Destinations possibles d'une règle d'installation
+const (
+	InstallTargetData    = "data"
+	InstallTargetScripts = "scripts"
+)
+
+// InstallRule indique où installer un dossier (ou un fichier) de l'archive : From est
+// un chemin dans l'archive, To vaut InstallTargetData ou InstallTargetScripts. Quand
+// un mod déclare des règles, les fichiers qu'aucune règle ne couvre ne sont pas installés.
+type InstallRule struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// DownloadMirrors retourne les miroirs du mod dans l'ordre du catalogue, DownloadURL
+// en premier s'il n'y figure pas déjà
+func (m *Mod) DownloadMirrors() []Mirror {
+	mirrors := make([]Mirror, 0, len(m.Mirrors)+1)
+	seen := make(map[string]bool)
+	if m.DownloadURL != "" {
+		mirrors = append(mirrors, Mirror{URL: m.DownloadURL})
+		seen[m.DownloadURL] = true
+	}
+	for _, mirror := range m.Mirrors {
+		if mirror.URL == "" {
+			continue
+		}
+		if seen[mirror.URL] {
+			// Le lien principal peut être répété pour lui associer un checksum
+			for i := range mirrors {
+				if mirrors[i].URL == mirror.URL && mirrors[i].Checksum == "" {
+					mirrors[i].Checksum = mirror.Checksum
+				}
+			}
+			continue
+		}
+		mirrors = append(mirrors, mirror)
+		seen[mirror.URL] = true
+	}
+	return mirrors
 }
 
 // GetInstallSize retourne la taille d'installation estimée
@@ -0,0 +1,117 @@
+// models/version.go
+package models
+
+import (
+	"strconv"
+	"strings"
+)
+
+// CompareVersions compare deux numéros de version et retourne -1, 0 ou 1. Les versions
+// de forme semver (8.2.0, v3.1, 1.0-beta.2) sont comparées numériquement, les parties
+// absentes valant 0 ; les autres sont comparées par ordre lexical et classées avant.
+func CompareVersions(a, b string) int {
+	va, okA := parseVersion(a)
+	vb, okB := parseVersion(b)
+	switch {
+	case okA && okB:
+		if c := compareNumbers(va.core, vb.core); c != 0 {
+			return c
+		}
+		return comparePrerelease(va.pre, vb.pre)
+	case okA:
+		return 1
+	case okB:
+		return -1
+	default:
+		return strings.Compare(a, b)
+	}
+}
+
+// IsPrerelease indique si la version est une préversion (1.0-beta, 2.0.0-rc.1)
+func IsPrerelease(version string) bool {
+	v, ok := parseVersion(version)
+	return ok && len(v.pre) > 0
+}
+
+type version struct {
+	core []int
+	pre  []string
+}
+
+func parseVersion(text string) (version, bool) {
+	text = strings.TrimSpace(text)
+	text = strings.TrimPrefix(strings.TrimPrefix(text, "v"), "V")
+	if i := strings.IndexByte(text, '+'); i >= 0 {
+		text = text[:i] // Les métadonnées de build n'entrent pas dans l'ordre
+	}
+
+	core, pre, _ := strings.Cut(text, "-")
+	if core == "" {
+		return version{}, false
+	}
+
+	var v version
+	for _, part := range strings.Split(core, ".") {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return version{}, false
+		}
+		v.core = append(v.core, n)
+	}
+	if pre != "" {
+		v.pre = strings.Split(pre, ".")
+	}
+	return v, true
+}
+
+func compareNumbers(a, b []int) int {
+	for i := 0; i < len(a) || i < len(b); i++ {
+		var x, y int
+		if i < len(a) {
+			x = a[i]
+		}
+		if i < len(b) {
+			y = b[i]
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// comparePrerelease suit semver : une version finale passe avant ses préversions,
+// les identifiants numériques sont comparés comme des nombres et avant les autres
+func comparePrerelease(a, b []string) int {
+	switch {
+	case len(a) == 0 && len(b) == 0:
+		return 0
+	case len(a) == 0:
+		return 1
+	case len(b) == 0:
+		return -1
+	}
+
+	for i := 0; i < len(a) && i < len(b); i++ {
+		x, errX := strconv.Atoi(a[i])
+		y, errY := strconv.Atoi(b[i])
+		var c int
+		switch {
+		case errX == nil && errY == nil:
+			c = compareNumbers([]int{x}, []int{y})
+		case errX == nil:
+			c = -1
+		case errY == nil:
+			c = 1
+		default:
+			c = strings.Compare(a[i], b[i])
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return compareNumbers([]int{len(a)}, []int{len(b)})
+}
@@ -0,0 +1,306 @@
+// services/backup.go
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"mod-installer/models"
+	"mod-installer/utils"
+)
+
+const backupIndexFile = "index.json"
+
+// BackupEntry décrit un fichier sauvegardé avant d'être écrasé
+type BackupEntry struct {
+	Original string `json:"original"` // Chemin absolu du fichier d'origine
+	Backup   string `json:"backup"`   // Nom du fichier dans le dossier de sauvegarde
+	Size     int64  `json:"size"`
+	SHA256   string `json:"sha256"`
+}
+
+// BackupIndex est l'index d'un jeu de sauvegardes créé par une installation
+type BackupIndex struct {
+	ID        string        `json:"id"`
+	ModID     string        `json:"mod_id"`
+	CreatedAt time.Time     `json:"created_at"`
+	Entries   []BackupEntry `json:"entries"`
+}
+
+// BackupSet regroupe les fichiers écrasés par une installation. Le dossier n'est
+// créé qu'à la première sauvegarde.
+type BackupSet struct {
+	dir   string
+	index BackupIndex
+	saved map[string]bool
+}
+
+func newBackupSet(backupsDir, modID string) *BackupSet {
+	now := time.Now()
+	safeID := strings.NewReplacer("/", "_", "\\", "_", ":", "_").Replace(modID)
+	id := fmt.Sprintf("%s_%d", safeID, now.UnixNano())
+	return &BackupSet{
+		dir:   filepath.Join(backupsDir, id),
+		index: BackupIndex{ID: id, ModID: modID, CreatedAt: now},
+		saved: make(map[string]bool),
+	}
+}
+
+// loadBackupSet charge un jeu de sauvegardes existant
+func loadBackupSet(backupsDir, id string) (*BackupSet, error) {
+	dir := filepath.Join(backupsDir, id)
+	data, err := os.ReadFile(filepath.Join(dir, backupIndexFile))
+	if err != nil {
+		return nil, fmt.Errorf("index de sauvegarde %s illisible: %w", id, err)
+	}
+
+	bs := &BackupSet{dir: dir, saved: make(map[string]bool)}
+	if err := json.Unmarshal(data, &bs.index); err != nil {
+		return nil, fmt.Errorf("index de sauvegarde %s corrompu: %w", id, err)
+	}
+	for _, entry := range bs.index.Entries {
+		bs.saved[filepath.Clean(entry.Original)] = true
+	}
+	return bs, nil
+}
+
+// ID retourne l'identifiant du jeu de sauvegardes
+func (bs *BackupSet) ID() string {
+	return bs.index.ID
+}
+
+// IsEmpty indique si aucun fichier n'a été sauvegardé
+func (bs *BackupSet) IsEmpty() bool {
+	return len(bs.index.Entries) == 0
+}
+
+// Backup copie path dans le jeu de sauvegardes. Un fichier déjà sauvegardé dans ce
+// jeu n'est pas recopié : c'est la version d'origine qui compte.
+func (bs *BackupSet) Backup(path string) error {
+	path = filepath.Clean(path)
+	if bs.saved[path] {
+		return nil
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+
+	sum, err := utils.CalculateSHA256(path)
+	if err != nil {
+		return err
+	}
+
+	name := bs.nextName(path)
+	if err := utils.CopyFile(path, filepath.Join(bs.dir, name)); err != nil {
+		return err
+	}
+
+	bs.index.Entries = append(bs.index.Entries, BackupEntry{
+		Original: path,
+		Backup:   name,
+		Size:     info.Size(),
+		SHA256:   sum,
+	})
+	bs.saved[path] = true
+
+	// L'index est réécrit à chaque ajout pour rester exploitable après un crash
+	return bs.save()
+}
+
+// adopt enregistre src comme sauvegarde de original, à la place d'une éventuelle
+// sauvegarde déjà faite dans ce jeu (reprise des sauvegardes d'une version remplacée)
+func (bs *BackupSet) adopt(original, src string) error {
+	original = filepath.Clean(original)
+	info, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+	sum, err := utils.CalculateSHA256(src)
+	if err != nil {
+		return err
+	}
+
+	entry := BackupEntry{
+		Original: original,
+		Backup:   bs.nextName(original),
+		Size:     info.Size(),
+		SHA256:   sum,
+	}
+	for i := range bs.index.Entries {
+		if filepath.Clean(bs.index.Entries[i].Original) == original {
+			entry.Backup = bs.index.Entries[i].Backup
+			bs.index.Entries[i] = entry
+			break
+		}
+	}
+	if err := utils.CopyFile(src, filepath.Join(bs.dir, entry.Backup)); err != nil {
+		return err
+	}
+	if !bs.saved[original] {
+		bs.index.Entries = append(bs.index.Entries, entry)
+		bs.saved[original] = true
+	}
+	return bs.save()
+}
+
+// forget retire la sauvegarde de original du jeu
+func (bs *BackupSet) forget(original string) error {
+	original = filepath.Clean(original)
+	if !bs.saved[original] {
+		return nil
+	}
+	for i, entry := range bs.index.Entries {
+		if filepath.Clean(entry.Original) == original {
+			os.Remove(filepath.Join(bs.dir, entry.Backup))
+			bs.index.Entries = append(bs.index.Entries[:i], bs.index.Entries[i+1:]...)
+			break
+		}
+	}
+	delete(bs.saved, original)
+	return bs.save()
+}
+
+// nextName retourne un nom de sauvegarde libre pour path
+func (bs *BackupSet) nextName(path string) string {
+	for n := len(bs.index.Entries); ; n++ {
+		name := fmt.Sprintf("%04d_%s", n, filepath.Base(path))
+		if !utils.FileExists(filepath.Join(bs.dir, name)) {
+			return name
+		}
+	}
+}
+
+// Lookup retourne le chemin de la sauvegarde de original, ou "" s'il n'a pas été sauvegardé
+func (bs *BackupSet) Lookup(original string) string {
+	original = filepath.Clean(original)
+	for _, entry := range bs.index.Entries {
+		if filepath.Clean(entry.Original) == original {
+			return filepath.Join(bs.dir, entry.Backup)
+		}
+	}
+	return ""
+}
+
+// Restore recopie tous les fichiers sauvegardés à leur emplacement d'origine
+func (bs *BackupSet) Restore() ([]string, error) {
+	restored := make([]string, 0, len(bs.index.Entries))
+	for _, entry := range bs.index.Entries {
+		backupPath := filepath.Join(bs.dir, entry.Backup)
+		if sum, err := utils.CalculateSHA256(backupPath); err != nil || sum != entry.SHA256 {
+			return restored, fmt.Errorf("sauvegarde corrompue pour %s", entry.Original)
+		}
+		if err := utils.CopyFile(backupPath, entry.Original); err != nil {
+			return restored, fmt.Errorf("erreur restauration %s: %w", entry.Original, err)
+		}
+		restored = append(restored, entry.Original)
+	}
+	return restored, nil
+}
+
+func (bs *BackupSet) save() error {
+	if err := os.MkdirAll(bs.dir, 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(bs.index, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(bs.dir, backupIndexFile), data, 0644)
+}
+
+// ListBackupSets retourne les index des jeux de sauvegardes, du plus récent au plus ancien
+func (is *InstallerService) ListBackupSets() ([]BackupIndex, error) {
+	matches, err := filepath.Glob(filepath.Join(is.backupDir, "*", backupIndexFile))
+	if err != nil {
+		return nil, err
+	}
+
+	indexes := make([]BackupIndex, 0, len(matches))
+	for _, match := range matches {
+		bs, err := loadBackupSet(is.backupDir, filepath.Base(filepath.Dir(match)))
+		if err != nil {
+			fmt.Printf("Sauvegarde ignorée: %v\n", err)
+			continue
+		}
+		indexes = append(indexes, bs.index)
+	}
+
+	sort.Slice(indexes, func(i, j int) bool {
+		return indexes[i].CreatedAt.After(indexes[j].CreatedAt)
+	})
+	return indexes, nil
+}
+
+// RestoreBackupSet remet en place les fichiers écrasés par une installation donnée.
+// Les fichiers restaurés ne sont plus ceux du mod : ils sont retirés de son manifeste,
+// qui est supprimé s'il ne lui reste aucun fichier.
+func (is *InstallerService) RestoreBackupSet(id string) ([]string, error) {
+	bs, err := loadBackupSet(is.backupDir, id)
+	if err != nil {
+		return nil, err
+	}
+	restored, err := bs.Restore()
+	if len(restored) > 0 {
+		if releaseErr := is.releaseRestoredFiles(id, restored); releaseErr != nil && err == nil {
+			err = fmt.Errorf("fichiers restaurés mais manifeste non mis à jour: %w", releaseErr)
+		}
+	}
+	return restored, err
+}
+
+// releaseRestoredFiles retire les fichiers restaurés du manifeste qui utilise le jeu
+// de sauvegardes id. Après une mise à jour, ce n'est pas forcément le mod qui l'a créé.
+func (is *InstallerService) releaseRestoredFiles(id string, restored []string) error {
+	manifests, err := models.ListInstallManifests(is.manifestDir)
+	if err != nil {
+		return err
+	}
+
+	paths := make(map[string]bool, len(restored))
+	for _, path := range restored {
+		paths[filepath.Clean(path)] = true
+	}
+
+	for _, manifest := range manifests {
+		if manifest.BackupID != id {
+			continue
+		}
+		kept := make([]models.InstalledFile, 0, len(manifest.Files))
+		for _, file := range manifest.Files {
+			if !paths[filepath.Clean(file.FullPath())] {
+				kept = append(kept, file)
+			}
+		}
+		if len(kept) == len(manifest.Files) {
+			continue
+		}
+
+		if len(kept) == 0 {
+			fmt.Printf("Mod %s retiré: tous ses fichiers ont été restaurés\n", manifest.ModID)
+			if err := models.RemoveInstallManifest(is.manifestDir, manifest.ModID); err != nil {
+				return err
+			}
+			continue
+		}
+		manifest.Files = kept
+		if err := manifest.Save(is.manifestDir); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeleteBackupSet supprime un jeu de sauvegardes
+func (is *InstallerService) DeleteBackupSet(id string) error {
+	if id == "" || strings.ContainsAny(id, `/\`) {
+		return fmt.Errorf("identifiant de sauvegarde invalide: %q", id)
+	}
+	return os.RemoveAll(filepath.Join(is.backupDir, id))
+}
@@ -0,0 +1,136 @@
+// services/catalog.go
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"mod-installer/api"
+	"mod-installer/config"
+	"mod-installer/models"
+)
+
+// CatalogFetcher charge le catalogue depuis le réseau
+type CatalogFetcher func() (map[string]models.Mod, error)
+
+// CatalogStatus indique d'où viennent les mods affichés
+type CatalogStatus int
+
+const (
+	CatalogNone   CatalogStatus = iota // Aucun catalogue : ni réseau ni cache
+	CatalogCached                      // Dernier catalogue enregistré
+	CatalogLive                        // Catalogue à jour, chargé depuis le réseau
+)
+
+// CatalogSnapshot est un état du catalogue. Err contient l'erreur du dernier
+// rafraîchissement quand celui-ci a échoué.
+type CatalogSnapshot struct {
+	Mods      map[string]models.Mod
+	Status    CatalogStatus
+	FetchedAt time.Time
+	Changes   CatalogChanges // Différences avec le catalogue enregistré précédemment
+	Err       error
+}
+
+// Age retourne l'ancienneté des données
+func (s CatalogSnapshot) Age() time.Duration {
+	if s.FetchedAt.IsZero() {
+		return 0
+	}
+	return time.Since(s.FetchedAt)
+}
+
+// catalogCacheFile est le format du catalogue enregistré sur le disque
+type catalogCacheFile struct {
+	FetchedAt time.Time             `json:"fetched_at"`
+	Mods      map[string]models.Mod `json:"mods"`
+}
+
+// CatalogService conserve le dernier catalogue chargé avec succès pour l'afficher
+// immédiatement au démarrage, puis le rafraîchit depuis le réseau
+type CatalogService struct {
+	cachePath string
+	fetch     CatalogFetcher
+	mu        sync.Mutex
+}
+
+func NewCatalogService(cfg *config.Config) *CatalogService {
+	// Un catalogue local de test ne remplace pas le cache du catalogue publié
+	cacheName := "catalog.json"
+	if cfg.LocalCatalogPath != "" {
+		cacheName = "catalog-local.json"
+	}
+	return &CatalogService{
+		cachePath: filepath.Join(cfg.CacheDir(), cacheName),
+		fetch:     api.FetchAllMods,
+	}
+}
+
+// SetFetcher remplace la source réseau du catalogue (sources configurées, tests)
+func (cs *CatalogService) SetFetcher(fetch CatalogFetcher) {
+	cs.fetch = fetch
+}
+
+// LoadCached retourne le catalogue enregistré, sans accès réseau
+func (cs *CatalogService) LoadCached() CatalogSnapshot {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	return cs.loadCached()
+}
+
+func (cs *CatalogService) loadCached() CatalogSnapshot {
+	data, err := os.ReadFile(cs.cachePath)
+	if err != nil {
+		return CatalogSnapshot{Status: CatalogNone}
+	}
+	var cached catalogCacheFile
+	if err := json.Unmarshal(data, &cached); err != nil || len(cached.Mods) == 0 {
+		fmt.Printf("Cache du catalogue illisible: %s\n", cs.cachePath)
+		return CatalogSnapshot{Status: CatalogNone}
+	}
+	return CatalogSnapshot{Mods: cached.Mods, Status: CatalogCached, FetchedAt: cached.FetchedAt}
+}
+
+// Refresh charge le catalogue depuis le réseau et l'enregistre ; Changes indique ce
+// qui a changé depuis le catalogue enregistré. En cas d'échec, le catalogue en cache
+// est retourné avec l'erreur.
+func (cs *CatalogService) Refresh() CatalogSnapshot {
+	mods, err := cs.fetch()
+
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	if err != nil {
+		snapshot := cs.loadCached()
+		snapshot.Err = err
+		return snapshot
+	}
+
+	snapshot := CatalogSnapshot{Mods: mods, Status: CatalogLive, FetchedAt: time.Now()}
+	if previous := cs.loadCached(); previous.Status == CatalogCached {
+		snapshot.Changes = DiffCatalogs(previous.Mods, mods)
+	}
+	if err := cs.save(snapshot); err != nil {
+		fmt.Printf("Erreur enregistrement du catalogue: %v\n", err)
+	}
+	return snapshot
+}
+
+func (cs *CatalogService) save(snapshot CatalogSnapshot) error {
+	data, err := json.MarshalIndent(catalogCacheFile{FetchedAt: snapshot.FetchedAt, Mods: snapshot.Mods}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(cs.cachePath), 0755); err != nil {
+		return err
+	}
+	tmp := cs.cachePath + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, cs.cachePath)
+}
@@ -0,0 +1,162 @@
+// services/conflicts.go
+package services
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"mod-installer/models"
+	"mod-installer/utils"
+	"mod-installer/utils/ntw"
+)
+
+// FileOwner est un mod qui écrit un fichier
+type FileOwner struct {
+	ModID     string
+	Name      string
+	Installed bool // Déjà installé (sinon prévu par l'installation en cours)
+}
+
+// FileConflict est un fichier du jeu écrit par plusieurs mods
+type FileConflict struct {
+	Path   string      // Destination : "data/<chemin>" ou "scripts/<chemin>"
+	Owners []FileOwner // Dans l'ordre d'installation : les mods installés, puis ceux prévus
+}
+
+// DefaultWinner retourne le mod dont la copie reste en place si rien n'est choisi :
+// le dernier installé
+func (c FileConflict) DefaultWinner() string {
+	return c.Owners[len(c.Owners)-1].ModID
+}
+
+// CheckFileConflicts liste, sans rien extraire, les fichiers que les mods prévus
+// écriraient (archives[mod.ID] est l'archive téléchargée du mod) et retourne ceux
+// qu'un autre mod prévu ou déjà installé écrit aussi. Les versions installées d'un
+// mod prévu ne sont pas comptées : elles sont réinstallées ou remplacées.
+func (is *InstallerService) CheckFileConflicts(mods []models.Mod, archives map[string]string) ([]FileConflict, error) {
+	owners := make(map[string][]FileOwner)
+	paths := make(map[string]string) // Clé -> destination telle qu'affichée
+	add := func(dest string, owner FileOwner) {
+		key := fileKey(dest)
+		for _, existing := range owners[key] {
+			if existing.ModID == owner.ModID {
+				return
+			}
+		}
+		if _, ok := paths[key]; !ok {
+			paths[key] = dest
+		}
+		owners[key] = append(owners[key], owner)
+	}
+
+	planned := make(map[string]bool, len(mods)*2)
+	for i := range mods {
+		planned[mods[i].ID] = true
+		planned[mods[i].GroupKey()] = true
+	}
+
+	installed, err := is.InstalledMods()
+	if err != nil {
+		return nil, err
+	}
+	for _, manifest := range installed {
+		if planned[manifest.ModID] || (manifest.Group != "" && planned[manifest.Group]) {
+			continue
+		}
+		for _, file := range manifest.Files {
+			if dest, ok := is.installedDestination(file); ok {
+				add(dest, FileOwner{ModID: manifest.ModID, Name: manifest.Name, Installed: true})
+			}
+		}
+	}
+
+	for i := range mods {
+		archivePath, ok := archives[mods[i].ID]
+		if !ok {
+			return nil, fmt.Errorf("archive de %s non téléchargée", mods[i].Name)
+		}
+		entries, err := utils.ListArchiveEntries(archivePath)
+		if err != nil {
+			return nil, fmt.Errorf("lecture de l'archive de %s: %w", mods[i].Name, err)
+		}
+		for _, entry := range entries {
+			if dest, ok := entryDestination(&mods[i], entry.Name); ok {
+				add(dest, FileOwner{ModID: mods[i].ID, Name: mods[i].Name})
+			}
+		}
+	}
+
+	conflicts := make([]FileConflict, 0)
+	for key, fileOwners := range owners {
+		if len(fileOwners) > 1 {
+			conflicts = append(conflicts, FileConflict{Path: paths[key], Owners: fileOwners})
+		}
+	}
+	sort.Slice(conflicts, func(i, j int) bool { return conflicts[i].Path < conflicts[j].Path })
+	return conflicts, nil
+}
+
+// entryDestination retourne la destination d'une entrée d'archive, d'après les règles
+// d'installation du mod ou, à défaut, le classement par extension des extracteurs
+func entryDestination(mod *models.Mod, name string) (string, bool) {
+	name = path.Clean(strings.Trim(strings.ReplaceAll(name, "\\", "/"), "/"))
+	if name == "." || name == ".." || strings.HasPrefix(name, "../") {
+		return "", false
+	}
+
+	if len(mod.Installation) > 0 {
+		rule, rel, ok := matchInstallRule(mod.Installation, name)
+		if !ok {
+			return "", false
+		}
+		return rule.To + "/" + rel, true
+	}
+	return ntw.GetDestinationPath(models.InstallTargetScripts, models.InstallTargetData, name) + "/" + name, true
+}
+
+// installedDestination retourne la destination d'un fichier installé dans le jeu courant
+func (is *InstallerService) installedDestination(file models.InstalledFile) (string, bool) {
+	switch filepath.Clean(file.Root) {
+	case filepath.Clean(is.GetDataPath()):
+		return models.InstallTargetData + "/" + filepath.ToSlash(file.Path), true
+	case filepath.Clean(is.GetScriptsPath()):
+		return models.InstallTargetScripts + "/" + filepath.ToSlash(file.Path), true
+	}
+	return "", false
+}
+
+// fileKey normalise une destination : le jeu tourne sous Windows, où la casse des
+// noms de fichiers est ignorée
+func fileKey(dest string) string {
+	return strings.ToLower(dest)
+}
+
+// skipStagedFiles retire de la zone de staging les fichiers dont un autre mod doit
+// rester le propriétaire (skip contient des clés de fileKey)
+func skipStagedFiles(written []utils.ExtractedFile, skip map[string]bool) ([]utils.ExtractedFile, error) {
+	if len(skip) == 0 {
+		return written, nil
+	}
+
+	kept := make([]utils.ExtractedFile, 0, len(written))
+	for _, file := range written {
+		rel, err := utils.GetRelativePath(file.DestRoot, file.Path)
+		if err != nil {
+			return nil, err
+		}
+		dest := filepath.Base(file.DestRoot) + "/" + filepath.ToSlash(rel)
+		if skip[fileKey(dest)] {
+			fmt.Printf("Fichier conservé pour un autre mod: %s\n", dest)
+			if err := os.Remove(file.Path); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		kept = append(kept, file)
+	}
+	return kept, nil
+}
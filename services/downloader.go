@@ -6,14 +6,17 @@ import (
 	"crypto/sha256"
 	"fmt"
 	"io"
+	"mime"
 	"net/http"
+	"net/http/cookiejar"
 	"os"
+	"path"
 	"path/filepath"
-	"regexp"
 	"strings"
 	"time"
 
 	"mod-installer/models"
+	"mod-installer/utils"
 )
 
 type ProgressCallback func(downloaded, total int64)
@@ -23,21 +26,32 @@ type DownloadService struct {
 	tempDir   string
 	cacheDir  string
 	verifySum bool
+	sources   []DownloadSource
+	mirrors   *mirrorStats
 }
 
 func NewDownloadService(tempDir string, verifyChecksum bool) *DownloadService {
+	// Le cookie jar conserve les cookies de confirmation de Google Drive
+	jar, _ := cookiejar.New(nil)
 	ds := &DownloadService{
-		client:    &http.Client{Timeout: 10 * time.Minute},
+		client:    &http.Client{Timeout: 10 * time.Minute, Jar: jar},
 		tempDir:   tempDir,
 		verifySum: verifyChecksum,
 	}
+	ds.sources = defaultSources(ds.client)
 	ds.cacheDir = filepath.Join(ds.tempDir, "download_cache")
 	ds.ensureDirectoryExists(ds.cacheDir)
+	ds.mirrors = loadMirrorStats(filepath.Join(ds.cacheDir, "mirrors.json"))
 	ds.ensureDirectoryExists(ds.tempDir)
 	return ds
 }
 
 
+// SetTransport remplace le transport HTTP utilisé pour les téléchargements
+// (proxy, tests hors ligne)
+func (ds *DownloadService) SetTransport(transport http.RoundTripper) {
+	ds.client.Transport = transport
+}
 
 func (ds *DownloadService) ensureDirectoryExists(dir string) error {
 	if _, err := os.Stat(dir); os.IsNotExist(err) {
@@ -55,49 +69,54 @@ func (ds *DownloadService) generateCacheKey(mod *models.Mod) string {
 	return fmt.Sprintf("%s_%s_%s", safeName, safeVersion, urlHash)
 }
 
-func (ds *DownloadService) getCachedFilePath(mod *models.Mod) string {
+// getCachedFilePath retourne l'emplacement en cache d'un fichier du format donné.
+// Les .pack bruts gardent leur nom d'origine, qui est celui installé dans data/.
+func (ds *DownloadService) getCachedFilePath(mod *models.Mod, format utils.ArchiveFormat, fileName string) string {
 	cacheKey := ds.generateCacheKey(mod)
-	extension := ".zip"
-	if strings.Contains(mod.DownloadURL, ".rar") {
-		extension = ".rar"
-	} else if strings.Contains(mod.DownloadURL, ".7z") {
-		extension = ".7z"
+	if format == utils.FormatPack {
+		return filepath.Join(ds.cacheDir, cacheKey, fileName)
 	}
-	return filepath.Join(ds.cacheDir, cacheKey+extension)
+	return filepath.Join(ds.cacheDir, cacheKey+format.Extension())
 }
 
-// NOUVEAU: Détection des dossiers Google Drive
-func (ds *DownloadService) isGoogleDriveFolder(url string) bool {
-	return strings.Contains(url, "drive.google.com/drive/folders/") ||
-		   (strings.Contains(url, "drive.google.com") && strings.Contains(url, "folders"))
-}
+// findCachedFile retourne le fichier en cache d'un mod quel que soit son format,
+// ou "" s'il n'a pas encore été téléchargé. Pour un dossier Google Drive, c'est
+// l'arborescence assemblée qui est retournée.
+func (ds *DownloadService) findCachedFile(mod *models.Mod) string {
+	folderPath := ds.getCachedFolderPath(mod)
+	if info, err := os.Stat(folderPath); err == nil && info.IsDir() {
+		return folderPath
+	}
 
-func (ds *DownloadService) isGoogleDriveURL(url string) bool {
-	return strings.Contains(url, "drive.google.com") || strings.Contains(url, "docs.google.com")
-}
+	cacheKey := ds.generateCacheKey(mod)
+	archives, _ := filepath.Glob(filepath.Join(ds.cacheDir, cacheKey+".*"))
+	packs, _ := filepath.Glob(filepath.Join(ds.cacheDir, cacheKey, "*.pack"))
 
-func (ds *DownloadService) convertGoogleDriveURL(url string) string {
-	patterns := []string{
-		`/file/d/([a-zA-Z0-9_-]+)`,
-		`id=([a-zA-Z0-9_-]+)`,
-		`/d/([a-zA-Z0-9_-]+)`,
-	}
-	
-	for _, pattern := range patterns {
-		re := regexp.MustCompile(pattern)
-		matches := re.FindStringSubmatch(url)
-		if len(matches) > 1 {
-			return fmt.Sprintf("https://drive.google.com/uc?export=download&id=%s", matches[1])
+	for _, match := range append(archives, packs...) {
+		if info, err := os.Stat(match); err == nil && info.Mode().IsRegular() {
+			return match
 		}
 	}
-	return url
+	return ""
+}
+
+// isGoogleDriveFolder détecte les dossiers Google Drive, téléchargés fichier par fichier
+func (ds *DownloadService) isGoogleDriveFolder(url string) bool {
+	return isGoogleDriveFolderURL(url)
 }
 
 func (ds *DownloadService) IsModCached(mod *models.Mod) bool {
-	cachedPath := ds.getCachedFilePath(mod)
+	cachedPath := ds.findCachedFile(mod)
+	if cachedPath == "" {
+		return false
+	}
+	// Un dossier n'est placé en cache qu'une fois entièrement assemblé
+	if info, err := os.Stat(cachedPath); err == nil && info.IsDir() {
+		return true
+	}
 	if info, err := os.Stat(cachedPath); err == nil && info.Size() > 1024 {
-		if ds.verifySum && mod.Checksum != "" {
-			if err := ds.verifyChecksum(cachedPath, mod.Checksum); err != nil {
+		if checksum := ds.cachedChecksum(mod); ds.verifySum && checksum != "" {
+			if err := ds.verifyChecksum(cachedPath, checksum); err != nil {
 				os.Remove(cachedPath)
 				return false
 			}
@@ -109,20 +128,18 @@ func (ds *DownloadService) IsModCached(mod *models.Mod) bool {
 
 func (ds *DownloadService) GetCachedModPath(mod *models.Mod) string {
 	if ds.IsModCached(mod) {
-		return ds.getCachedFilePath(mod)
+		return ds.findCachedFile(mod)
 	}
 	return ""
 }
 
-// MODIFIÉ: Ajout de la détection des dossiers
+// DownloadMod retourne le chemin de l'archive du mod, ou de l'arborescence assemblée
+// pour un dossier Google Drive, en la téléchargeant si elle n'est pas en cache. Les
+// miroirs sont essayés dans l'ordre : le dernier qui a fonctionné d'abord, ceux qui
+// échouent de façon répétée en dernier.
 func (ds *DownloadService) DownloadMod(ctx context.Context, mod *models.Mod, callback ProgressCallback) (string, error) {
-	// NOUVEAU: Vérifier si c'est un dossier Google Drive
-	if ds.isGoogleDriveFolder(mod.DownloadURL) {
-		return "", fmt.Errorf("dossier Google Drive détecté. Pour télécharger:\n1. Allez sur %s\n2. Sélectionnez tout (Ctrl+A)\n3. Clic droit > Télécharger\n4. Utilisez le ZIP créé", mod.DownloadURL)
-	}
-
-	cachedPath := ds.getCachedFilePath(mod)
 	if ds.IsModCached(mod) {
+		cachedPath := ds.findCachedFile(mod)
 		fmt.Printf("Mod %s trouvé en cache: %s\n", mod.ID, cachedPath)
 		if callback != nil {
 			callback(1, 1)
@@ -131,74 +148,217 @@ func (ds *DownloadService) DownloadMod(ctx context.Context, mod *models.Mod, cal
 	}
 	
 	fmt.Printf("Téléchargement du mod %s...\n", mod.ID)
+
+	mirrors := ds.mirrors.order(mod)
+	if len(mirrors) == 0 {
+		return "", fmt.Errorf("aucun lien de téléchargement pour %s", mod.ID)
+	}
+
+	// Les miroirs sont essayés dans l'ordre ; un échec n'interrompt pas les suivants
+	var lastErr error
+	for i, mirror := range mirrors {
+		if i > 0 {
+			fmt.Printf("Mod %s: essai du miroir %s\n", mod.ID, mirror.URL)
+		}
+		cachedPath, err := ds.downloadFromMirror(ctx, mod, mirror, callback)
+		if err == nil {
+			ds.mirrors.recordSuccess(mod.ID, mirror.URL)
+			return cachedPath, nil
+		}
+		if ctx.Err() != nil {
+			return "", err
+		}
+		fmt.Printf("Mod %s: échec du miroir %s: %v\n", mod.ID, mirror.URL, err)
+		ds.mirrors.recordFailure(mirror.URL, err)
+		lastErr = err
+	}
+
+	if len(mirrors) == 1 {
+		return "", lastErr
+	}
+	return "", fmt.Errorf("les %d miroirs ont échoué, dernier: %w", len(mirrors), lastErr)
+}
+
+// cachedChecksum retourne le checksum attendu du fichier en cache : celui du miroir
+// qui l'a fourni s'il en a un propre, sinon celui du mod
+func (ds *DownloadService) cachedChecksum(mod *models.Mod) string {
+	if mirror, ok := ds.mirrors.lastGood(mod); ok && mirror.Checksum != "" {
+		return mirror.Checksum
+	}
+	return mod.Checksum
+}
+
+// downloadFromMirror télécharge le mod depuis un miroir et le place en cache
+func (ds *DownloadService) downloadFromMirror(ctx context.Context, mod *models.Mod, mirror models.Mirror, callback ProgressCallback) (string, error) {
+	if ds.isGoogleDriveFolder(mirror.URL) {
+		return ds.downloadDriveFolder(ctx, mod, mirror.URL, callback)
+	}
+
+	checksum := mirror.Checksum
+	if checksum == "" {
+		checksum = mod.Checksum
+	}
 	
-	tempFilename := fmt.Sprintf("download_%s_%d.tmp", ds.generateCacheKey(mod), time.Now().Unix())
-	tempPath := filepath.Join(ds.tempDir, tempFilename)
+	// Fichier partiel stable : un téléchargement interrompu est repris au prochain essai
+	tempPath := ds.getPartialPath(mod)
 	
-	err := ds.downloadToFile(ctx, mod.DownloadURL, tempPath, callback)
+	fileName, err := ds.downloadToFile(ctx, mirror.URL, tempPath, callback)
 	if err != nil {
-		os.Remove(tempPath)
 		return "", fmt.Errorf("erreur téléchargement: %w", err)
 	}
 	
 	if info, err := os.Stat(tempPath); err == nil && info.Size() < 1024 {
-		os.Remove(tempPath)
+		removePartial(tempPath)
 		return "", fmt.Errorf("fichier trop petit (%d bytes)", info.Size())
 	}
 	
-	if ds.verifySum && mod.Checksum != "" {
-		if err := ds.verifyChecksum(tempPath, mod.Checksum); err != nil {
-			os.Remove(tempPath)
+	if ds.verifySum && checksum != "" {
+		if err := ds.verifyChecksum(tempPath, checksum); err != nil {
+			removePartial(tempPath)
 			return "", fmt.Errorf("checksum invalide: %w", err)
 		}
 	}
-	
+	// Le format est déterminé d'après le contenu et non d'après l'URL
+	format, err := utils.DetectArchiveFormat(tempPath)
+	if err != nil {
+		removePartial(tempPath)
+		return "", err
+	}
+	switch {
+	case format == utils.FormatHTML:
+		removePartial(tempPath)
+		return "", fmt.Errorf("HTML reçu au lieu du fichier")
+	case format == utils.FormatPack:
+		if !strings.EqualFold(filepath.Ext(fileName), ".pack") {
+			fileName = strings.ReplaceAll(mod.ID, "/", "_") + ".pack"
+		}
+	case !format.IsArchive():
+		removePartial(tempPath)
+		return "", fmt.Errorf("format de fichier non reconnu pour %s", mod.ID)
+	}
+
+	cachedPath := ds.getCachedFilePath(mod, format, fileName)
+	if err := ds.ensureDirectoryExists(filepath.Dir(cachedPath)); err != nil {
+		return "", fmt.Errorf("erreur mise en cache: %w", err)
+	}
 	if err := os.Rename(tempPath, cachedPath); err != nil {
-		os.Remove(tempPath)
 		return "", fmt.Errorf("erreur mise en cache: %w", err)
 	}
+	os.Remove(partialMetaPath(tempPath))
 	
 	fmt.Printf("Mod %s mis en cache: %s\n", mod.ID, cachedPath)
 	return cachedPath, nil
 }
 
-func (ds *DownloadService) downloadToFile(ctx context.Context, url, filepath string, callback ProgressCallback) error {
-	downloadURL := url
-	if ds.isGoogleDriveURL(url) {
-		downloadURL = ds.convertGoogleDriveURL(url)
-	}
-	
-	req, err := http.NewRequestWithContext(ctx, "GET", downloadURL, nil)
+// downloadToFile télécharge url dans filepath via la source qui la reconnaît et
+// retourne le nom de fichier annoncé par le serveur (Content-Disposition) ou, à défaut,
+// celui de l'URL. Si un fichier partiel validé par ETag ou Last-Modified existe, le
+// téléchargement reprend avec Range/If-Range ; sinon il repart de zéro.
+func (ds *DownloadService) downloadToFile(ctx context.Context, url, filepath string, callback ProgressCallback) (string, error) {
+	source, err := ds.sourceFor(url)
 	if err != nil {
-		return err
+		return "", err
 	}
 	
-	resp, err := ds.client.Do(req)
+	partial := loadPartialDownload(filepath)
+	offset := resumeOffset(filepath, url, partial)
+	
+	resolveCtx := ctx
+	if offset > 0 {
+		resolveCtx = withResume(ctx, offset, partial.validator())
+	}
+	resolved, err := source.Resolve(resolveCtx, url)
 	if err != nil {
-		return err
+		return "", fmt.Errorf("%s: %w", source.Name(), err)
 	}
-	defer resp.Body.Close()
+	defer resolved.Close()
+	resp := resolved.Response
 	
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("erreur HTTP: %s", resp.Status)
+	switch resp.StatusCode {
+	case http.StatusOK:
+		// Pas de reprise possible (serveur sans Range ou fichier modifié) : on recommence
+		offset = 0
+	case http.StatusPartialContent:
+		if start, ok := contentRangeStart(resp); !ok || start != offset {
+			removePartial(filepath)
+			return "", fmt.Errorf("réponse partielle inattendue (%s), le téléchargement repartira de zéro", resp.Header.Get("Content-Range"))
+		}
+		fmt.Printf("Reprise du téléchargement à %d octets\n", offset)
+	case http.StatusRequestedRangeNotSatisfiable:
+		if offset > 0 && offset == partial.Total {
+			return partial.FileName, nil // déjà complet
+		}
+		removePartial(filepath)
+		return "", fmt.Errorf("reprise refusée par le serveur, le téléchargement repartira de zéro")
+	default:
+		return "", fmt.Errorf("erreur HTTP: %s", resp.Status)
 	}
 	
 	contentType := resp.Header.Get("Content-Type")
 	if strings.Contains(contentType, "text/html") {
-		return fmt.Errorf("HTML reçu au lieu du fichier")
+		return "", fmt.Errorf("HTML reçu au lieu du fichier")
+	}
+	
+	if offset == 0 {
+		partial = &partialDownload{
+			URL:          url,
+			ETag:         resp.Header.Get("ETag"),
+			LastModified: resp.Header.Get("Last-Modified"),
+			FileName:     resolved.FileName,
+			Total:        resolved.Size,
+		}
+		if err := partial.save(filepath); err != nil {
+			return "", err
+		}
 	}
 	
-	file, err := os.Create(filepath)
+	flags := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	if offset > 0 {
+		flags = os.O_WRONLY | os.O_APPEND
+	}
+	file, err := os.OpenFile(filepath, flags, 0644)
 	if err != nil {
-		return err
+		return "", err
 	}
 	defer file.Close()
 	
-	return ds.downloadWithProgress(resp.Body, file, resp.ContentLength, callback)
+	total := int64(-1)
+	if resolved.Size >= 0 {
+		total = offset + resolved.Size
+	}
+	return partial.FileName, ds.downloadWithProgress(resolved.Body, file, offset, total, callback)
+}
+
+// responseFileName extrait le nom de fichier d'une réponse HTTP : paramètre filename
+// (ou filename*) de Content-Disposition, sinon dernier segment de l'URL finale
+func responseFileName(resp *http.Response) string {
+	if disposition := resp.Header.Get("Content-Disposition"); disposition != "" {
+		if _, params, err := mime.ParseMediaType(disposition); err == nil {
+			if name := sanitizeFileName(params["filename"]); name != "" {
+				return name
+			}
+		}
+	}
+	if resp.Request != nil && resp.Request.URL != nil {
+		name := sanitizeFileName(path.Base(resp.Request.URL.Path))
+		if path.Ext(name) != "" {
+			return name
+		}
+	}
+	return ""
+}
+
+// sanitizeFileName ne garde que le nom de base pour éviter tout chemin dans le nom annoncé
+func sanitizeFileName(name string) string {
+	name = path.Base(strings.ReplaceAll(name, "\\", "/"))
+	if name == "." || name == "/" || name == ".." {
+		return ""
+	}
+	return name
 }
 
-func (ds *DownloadService) downloadWithProgress(src io.Reader, dst io.Writer, total int64, callback ProgressCallback) error {
-	var downloaded int64
+func (ds *DownloadService) downloadWithProgress(src io.Reader, dst io.Writer, offset, total int64, callback ProgressCallback) error {
+	downloaded := offset
 	buf := make([]byte, 64*1024)
 	
 	for {
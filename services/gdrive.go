@@ -0,0 +1,160 @@
+// services/gdrive.go
+package services
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// Nombre maximal de pages d'avertissement Google Drive franchies pour un fichier
+const maxDriveConfirmations = 3
+
+var (
+	driveFormRe   = regexp.MustCompile(`(?is)<form[^>]*id="download-form"[^>]*>(.*?)</form>`)
+	driveActionRe = regexp.MustCompile(`(?is)<form[^>]*action="([^"]+)"`)
+	driveInputRe  = regexp.MustCompile(`(?is)<input[^>]*type="hidden"[^>]*>`)
+	driveNameRe   = regexp.MustCompile(`(?is)\bname="([^"]*)"`)
+	driveValueRe  = regexp.MustCompile(`(?is)\bvalue="([^"]*)"`)
+	driveLinkRe   = regexp.MustCompile(`(?is)href="([^"]*confirm=[^"]*)"`)
+	driveQuotaRe  = regexp.MustCompile(`(?i)(quota exceeded|too many users have viewed or downloaded)`)
+)
+
+// GoogleDriveSource télécharge les fichiers partagés sur Google Drive, en franchissant
+// la page d'avertissement (« impossible d'analyser ce fichier ») affichée pour les gros
+// fichiers. Les cookies posés par Drive sont conservés dans le cookie jar du client.
+type GoogleDriveSource struct {
+	client *http.Client
+}
+
+func NewGoogleDriveSource(client *http.Client) *GoogleDriveSource {
+	return &GoogleDriveSource{client: client}
+}
+
+func (s *GoogleDriveSource) Name() string {
+	return "Google Drive"
+}
+
+// Matches accepte les liens de fichiers Drive ; les dossiers sont traités à part
+func (s *GoogleDriveSource) Matches(rawURL string) bool {
+	return isGoogleDriveURL(rawURL) && !isGoogleDriveFolderURL(rawURL)
+}
+
+func (s *GoogleDriveSource) Resolve(ctx context.Context, rawURL string) (*ResolvedDownload, error) {
+	downloadURL := convertGoogleDriveURL(rawURL)
+
+	for attempt := 0; ; attempt++ {
+		resolved, err := fetchFile(ctx, s.client, downloadURL)
+		if err != nil {
+			return nil, err
+		}
+		resp := resolved.Response
+
+		if !isHTMLResponse(resp) {
+			return resolved, nil
+		}
+
+		if attempt >= maxDriveConfirmations {
+			resolved.Close()
+			return nil, fmt.Errorf("Google Drive: confirmation du téléchargement impossible")
+		}
+
+		body, err := io.ReadAll(io.LimitReader(resp.Body, 2*1024*1024))
+		resolved.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		next, err := s.parseDriveConfirmation(body, resp.Request.URL)
+		if err != nil {
+			return nil, err
+		}
+		fmt.Printf("Google Drive: confirmation du téléchargement via %s\n", next)
+		downloadURL = next
+	}
+}
+
+func isGoogleDriveURL(url string) bool {
+	return strings.Contains(url, "drive.google.com") || strings.Contains(url, "docs.google.com")
+}
+
+func isGoogleDriveFolderURL(url string) bool {
+	return strings.Contains(url, "drive.google.com/drive/folders/") ||
+		(strings.Contains(url, "drive.google.com") && strings.Contains(url, "folders"))
+}
+
+var driveFileIDPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`/file/d/([a-zA-Z0-9_-]+)`),
+	regexp.MustCompile(`id=([a-zA-Z0-9_-]+)`),
+	regexp.MustCompile(`/d/([a-zA-Z0-9_-]+)`),
+}
+
+// convertGoogleDriveURL transforme un lien de partage en lien de téléchargement direct
+func convertGoogleDriveURL(url string) string {
+	for _, re := range driveFileIDPatterns {
+		matches := re.FindStringSubmatch(url)
+		if len(matches) > 1 {
+			return fmt.Sprintf("https://drive.google.com/uc?export=download&id=%s", matches[1])
+		}
+	}
+	return url
+}
+
+// parseDriveConfirmation retrouve l'URL de téléchargement confirmé dans la page
+// d'avertissement de Google Drive : formulaire download-form (id, export, confirm,
+// uuid), ancien lien « confirm=… » ou cookie download_warning.
+func (s *GoogleDriveSource) parseDriveConfirmation(body []byte, pageURL *url.URL) (string, error) {
+	page := string(body)
+
+	if form := driveFormRe.FindString(page); form != "" {
+		action := driveActionRe.FindStringSubmatch(form)
+		if action == nil {
+			return "", fmt.Errorf("Google Drive: formulaire de confirmation sans action")
+		}
+		target, err := pageURL.Parse(html.UnescapeString(action[1]))
+		if err != nil {
+			return "", fmt.Errorf("Google Drive: action de confirmation invalide: %w", err)
+		}
+
+		query := target.Query()
+		for _, input := range driveInputRe.FindAllString(form, -1) {
+			name := driveNameRe.FindStringSubmatch(input)
+			value := driveValueRe.FindStringSubmatch(input)
+			if name != nil && value != nil {
+				query.Set(html.UnescapeString(name[1]), html.UnescapeString(value[1]))
+			}
+		}
+		target.RawQuery = query.Encode()
+		return target.String(), nil
+	}
+
+	if link := driveLinkRe.FindStringSubmatch(page); link != nil {
+		target, err := pageURL.Parse(html.UnescapeString(link[1]))
+		if err != nil {
+			return "", fmt.Errorf("Google Drive: lien de confirmation invalide: %w", err)
+		}
+		return target.String(), nil
+	}
+
+	if s.client.Jar != nil {
+		for _, cookie := range s.client.Jar.Cookies(pageURL) {
+			if strings.HasPrefix(cookie.Name, "download_warning") {
+				target := *pageURL
+				query := target.Query()
+				query.Set("confirm", cookie.Value)
+				target.RawQuery = query.Encode()
+				return target.String(), nil
+			}
+		}
+	}
+
+	if driveQuotaRe.MatchString(page) {
+		return "", fmt.Errorf("Google Drive: quota de téléchargement dépassé pour ce fichier, réessayez plus tard")
+	}
+	return "", fmt.Errorf("HTML reçu au lieu du fichier")
+}
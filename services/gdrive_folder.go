@@ -0,0 +1,208 @@
+// services/gdrive_folder.go
+package services
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"mod-installer/models"
+	"mod-installer/utils"
+)
+
+// Profondeur maximale de sous-dossiers parcourus dans un dossier Google Drive
+const maxDriveFolderDepth = 5
+
+var (
+	driveFolderIDRe    = regexp.MustCompile(`/folders/([a-zA-Z0-9_-]+)`)
+	driveEntryIDRe     = regexp.MustCompile(`id="entry-([a-zA-Z0-9_-]+)"`)
+	driveEntryHrefRe   = regexp.MustCompile(`<a[^>]*href="([^"]+)"`)
+	driveEntryTitleRe  = regexp.MustCompile(`(?s)class="flip-entry-title"[^>]*>([^<]*)<`)
+	driveEntrySplitter = `class="flip-entry"`
+)
+
+// DriveEntry est un élément d'un dossier Google Drive
+type DriveEntry struct {
+	ID       string
+	Name     string
+	Path     string // Chemin relatif au dossier racine, séparé par des "/"
+	IsFolder bool
+}
+
+// driveFolderURL retourne la page de liste publique d'un dossier partagé
+func driveFolderURL(folderID string) string {
+	return fmt.Sprintf("https://drive.google.com/embeddedfolderview?id=%s", folderID)
+}
+
+// ListDriveFolder liste récursivement les fichiers d'un dossier Google Drive partagé
+func (ds *DownloadService) ListDriveFolder(ctx context.Context, folderURL string) ([]DriveEntry, error) {
+	matches := driveFolderIDRe.FindStringSubmatch(folderURL)
+	if matches == nil {
+		return nil, fmt.Errorf("identifiant de dossier Google Drive introuvable: %s", folderURL)
+	}
+
+	files := make([]DriveEntry, 0)
+	if err := ds.listDriveFolder(ctx, matches[1], "", 0, &files); err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("dossier Google Drive vide ou non partagé: %s", folderURL)
+	}
+	return files, nil
+}
+
+func (ds *DownloadService) listDriveFolder(ctx context.Context, folderID, prefix string, depth int, files *[]DriveEntry) error {
+	if depth > maxDriveFolderDepth {
+		return fmt.Errorf("dossier Google Drive trop profond: %s", prefix)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", driveFolderURL(folderID), nil)
+	if err != nil {
+		return err
+	}
+	resp, err := ds.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("liste du dossier Google Drive: erreur HTTP %s", resp.Status)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 8*1024*1024))
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range parseDriveFolderPage(string(body)) {
+		entry.Path = path.Join(prefix, entry.Name)
+		if entry.IsFolder {
+			if err := ds.listDriveFolder(ctx, entry.ID, entry.Path, depth+1, files); err != nil {
+				return err
+			}
+			continue
+		}
+		*files = append(*files, entry)
+	}
+	return nil
+}
+
+// parseDriveFolderPage extrait les entrées de la page embeddedfolderview
+func parseDriveFolderPage(page string) []DriveEntry {
+	chunks := strings.Split(page, driveEntrySplitter)
+	entries := make([]DriveEntry, 0, len(chunks))
+
+	for _, chunk := range chunks[1:] {
+		id := driveEntryIDRe.FindStringSubmatch(chunk)
+		title := driveEntryTitleRe.FindStringSubmatch(chunk)
+		if id == nil || title == nil {
+			continue
+		}
+
+		name := sanitizeFileName(html.UnescapeString(strings.TrimSpace(title[1])))
+		if name == "" {
+			continue
+		}
+
+		entry := DriveEntry{ID: id[1], Name: name}
+		if href := driveEntryHrefRe.FindStringSubmatch(chunk); href != nil {
+			entry.IsFolder = strings.Contains(href[1], "/folders/")
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// getCachedFolderPath retourne l'arbre d'installation assemblé d'un dossier Drive
+func (ds *DownloadService) getCachedFolderPath(mod *models.Mod) string {
+	return filepath.Join(ds.cacheDir, ds.generateCacheKey(mod)+".folder")
+}
+
+// downloadDriveFolder télécharge chaque fichier du dossier et assemble une arborescence
+// prête à installer : les archives sont extraites à leur emplacement, les autres
+// fichiers sont copiés tels quels. L'arbre n'est placé en cache qu'une fois complet.
+func (ds *DownloadService) downloadDriveFolder(ctx context.Context, mod *models.Mod, folderURL string, callback ProgressCallback) (string, error) {
+	files, err := ds.ListDriveFolder(ctx, folderURL)
+	if err != nil {
+		return "", err
+	}
+	fmt.Printf("Dossier Google Drive %s: %d fichier(s)\n", mod.ID, len(files))
+
+	workDir := filepath.Join(ds.tempDir, "folder_"+ds.generateCacheKey(mod))
+	downloadsDir := filepath.Join(workDir, "downloads")
+	treeDir := filepath.Join(workDir, "tree")
+	os.RemoveAll(treeDir)
+	if err := ds.ensureDirectoryExists(downloadsDir); err != nil {
+		return "", err
+	}
+
+	// Progression globale : chaque fichier compte pour une part égale
+	const unit = 1000
+	total := int64(len(files) * unit)
+
+	for i, file := range files {
+		fileURL := fmt.Sprintf("https://drive.google.com/uc?export=download&id=%s", file.ID)
+		partPath := filepath.Join(downloadsDir, file.ID+".part")
+
+		_, err := ds.downloadToFile(ctx, fileURL, partPath, func(downloaded, size int64) {
+			if callback != nil && size > 0 {
+				callback(int64(i*unit)+downloaded*unit/size, total)
+			}
+		})
+		if err != nil {
+			return "", fmt.Errorf("erreur téléchargement %s: %w", file.Path, err)
+		}
+
+		if err := ds.addToTree(ctx, partPath, treeDir, file); err != nil {
+			return "", err
+		}
+		removePartial(partPath)
+
+		if callback != nil {
+			callback(int64((i+1)*unit), total)
+		}
+	}
+
+	cachedPath := ds.getCachedFolderPath(mod)
+	os.RemoveAll(cachedPath)
+	if err := os.Rename(treeDir, cachedPath); err != nil {
+		return "", fmt.Errorf("erreur mise en cache: %w", err)
+	}
+	os.RemoveAll(workDir)
+
+	fmt.Printf("Dossier %s mis en cache: %s\n", mod.ID, cachedPath)
+	return cachedPath, nil
+}
+
+// addToTree place un fichier téléchargé dans l'arbre d'installation
+func (ds *DownloadService) addToTree(ctx context.Context, downloadedPath, treeDir string, file DriveEntry) error {
+	format, err := utils.DetectArchiveFormat(downloadedPath)
+	if err != nil {
+		return err
+	}
+	if format == utils.FormatHTML {
+		return fmt.Errorf("HTML reçu au lieu du fichier %s", file.Path)
+	}
+
+	destDir := filepath.Join(treeDir, filepath.FromSlash(path.Dir(file.Path)))
+	if format.IsArchive() {
+		extract, err := utils.ExtractorFor(format)
+		if err != nil {
+			return err
+		}
+		if _, err := extract(ctx, destDir, destDir, downloadedPath, nil); err != nil {
+			return fmt.Errorf("erreur extraction %s: %w", file.Path, err)
+		}
+		return nil
+	}
+
+	return utils.MoveFile(downloadedPath, filepath.Join(destDir, file.Name))
+}
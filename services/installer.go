@@ -7,6 +7,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"mod-installer/models"
 	"mod-installer/config"
@@ -19,6 +20,11 @@ type InstallProgressCallback = utils.InstallProgressCallback
 // InstallerService gère l'installation des mods
 type InstallerService struct {
 	gamePath, scriptsPath, TempDir string
+	manifestDir                    string // Manifestes des mods installés
+	vanillaDir                     string // Sauvegardes de VanillaService
+	backupDir                      string // Jeux de sauvegardes créés avant écrasement
+	journalDir                     string // Journaux des commits d'installation en cours
+	createBackups                  bool
 }
 
 // EnsureDirectoryExists crée un répertoire s'il n'existe pas
@@ -34,6 +40,12 @@ func NewInstallerService(cfg *config.Config) *InstallerService {
 		gamePath:    cfg.GamePath,
 		scriptsPath: cfg.ScriptsPath,
 		TempDir:     cfg.TempPath,
+		manifestDir: filepath.Join(cfg.CacheDir(), "installed"),
+		vanillaDir:  filepath.Join(cfg.TempPath, "vanilla"),
+		backupDir:   filepath.Join(cfg.CacheDir(), "backups"),
+		journalDir:  filepath.Join(cfg.CacheDir(), "journal"),
+
+		createBackups: cfg.CreateBackups,
 	}
 
 	
@@ -66,6 +78,12 @@ func (is *InstallerService) IsScriptsPathValid() bool {
 }
 
 func (is *InstallerService) InstallMod(ctx context.Context, mod *models.Mod, archivePath string, callback InstallProgressCallback) error {
+	return is.installMod(ctx, mod, archivePath, nil, callback)
+}
+
+// installMod installe le mod sans écrire les fichiers de skip (clés de fileKey), laissés
+// au mod choisi lors de la vérification des conflits
+func (is *InstallerService) installMod(ctx context.Context, mod *models.Mod, archivePath string, skip map[string]bool, callback InstallProgressCallback) error {
 	if !is.IsGamePathValid() {
 		return fmt.Errorf("chemin du jeu invalide: %s", is.GetDataPath())
 	}
@@ -73,30 +91,132 @@ func (is *InstallerService) InstallMod(ctx context.Context, mod *models.Mod, arc
 		return fmt.Errorf("chemin scripts invalide: %s", is.GetDataPath())
 	}
 
-	ext := strings.ToLower(filepath.Ext(archivePath))
-	switch ext {
-	case ".zip":
-		return utils.ExtractZip(ctx, is.GetScriptsPath(), is.GetDataPath(), archivePath, callback)
-	case ".rar":
-		return utils.ExtractRar(ctx, is.GetScriptsPath(), is.GetDataPath(), archivePath, callback)
-	case ".7z":
-		return fmt.Errorf("format 7z non supporté dans cette version")
-	default:
-		return fmt.Errorf("format d'archive non supporté: %s", ext)
+	// 1. Extraction dans une zone de staging : le jeu n'est pas touché
+	stageDir, written, err := is.stageArchive(ctx, mod, archivePath, callback)
+	if err != nil {
+		return err
+	}
+	extracted := len(written)
+	written, err = skipStagedFiles(written, skip)
+	if err != nil {
+		os.RemoveAll(stageDir)
+		return err
+	}
+	if extracted > 0 && len(written) == 0 {
+		// Tous les fichiers sont laissés à d'autres mods : rien à copier, mais le mod
+		// est enregistré comme installé
+		os.RemoveAll(stageDir)
+		fmt.Printf("Aucun fichier à copier pour %s: tous sont conservés pour d'autres mods\n", mod.Name)
+		return is.recordInstallation(mod, archivePath, "", nil)
+	}
+
+	// 2. Validation du contenu extrait
+	if err := validateStage(stageDir, written); err != nil {
+		os.RemoveAll(stageDir)
+		return fmt.Errorf("archive invalide: %w", err)
+	}
+
+	// 3. Commit journalisé vers data/ et scripts/, annulé en cas d'échec
+	journal, err := is.planCommit(mod, archivePath, stageDir, written)
+	if err != nil {
+		os.RemoveAll(stageDir)
+		return err
+	}
+	return is.commit(ctx, journal)
+}
+
+// recordInstallation enregistre le manifeste des fichiers écrits par l'installation
+func (is *InstallerService) recordInstallation(mod *models.Mod, archivePath, backupID string, written []utils.ExtractedFile) error {
+	manifest := &models.InstallManifest{
+		ModID:       mod.ID,
+		Group:       mod.GroupKey(),
+		Name:        mod.Name,
+		Version:     mod.Version,
+		GamePath:    is.gamePath,
+		ArchivePath: archivePath,
+		InstalledAt: time.Now(),
+		BackupID:    backupID,
+		Files:       make([]models.InstalledFile, 0, len(written)),
 	}
+
+	for _, file := range written {
+		info, err := os.Stat(file.Path)
+		if err != nil {
+			return err
+		}
+
+		relPath, err := utils.GetRelativePath(file.DestRoot, file.Path)
+		if err != nil {
+			return err
+		}
+
+		sum, err := utils.CalculateSHA256(file.Path)
+		if err != nil {
+			return err
+		}
+
+		manifest.Files = append(manifest.Files, models.InstalledFile{
+			Root:     file.DestRoot,
+			Path:     relPath,
+			Size:     info.Size(),
+			SHA256:   sum,
+			ModTime:  info.ModTime(),
+			Replaced: file.Replaced,
+		})
+	}
+
+	return manifest.Save(is.manifestDir)
 }
 
+// GetInstallManifest retourne le manifeste d'installation d'un mod (nil s'il n'a jamais été installé)
+func (is *InstallerService) GetInstallManifest(mod *models.Mod) (*models.InstallManifest, error) {
+	return models.LoadInstallManifest(is.manifestDir, mod.ID)
+}
+
+// CheckInstallation vérifie l'état sur le disque des fichiers installés par un mod
+func (is *InstallerService) CheckInstallation(mod *models.Mod) (models.ManifestCheck, error) {
+	manifest, err := is.GetInstallManifest(mod)
+	if err != nil {
+		return models.ManifestCheck{}, err
+	}
+	if manifest == nil || filepath.Clean(manifest.GamePath) != filepath.Clean(is.gamePath) {
+		return models.ManifestCheck{State: models.InstallStateNone}, nil
+	}
+	return manifest.Check(), nil
+}
+
+// GetInstallationStatus indique si le mod est installé intégralement. Une erreur
+// est retournée si l'installation a été partiellement supprimée ou modifiée.
 func (is *InstallerService) GetInstallationStatus(mod *models.Mod) (bool, error) {
-	return false, nil
+	check, err := is.CheckInstallation(mod)
+	if err != nil {
+		return false, err
+	}
+
+	switch check.State {
+	case models.InstallStateComplete:
+		return true, nil
+	case models.InstallStatePartial:
+		return false, fmt.Errorf("installation incomplète de %s: %d fichier(s) manquant(s)", mod.Name, len(check.Missing))
+	case models.InstallStateModified:
+		return false, fmt.Errorf("installation modifiée de %s: %d fichier(s) modifié(s)", mod.Name, len(check.Modified))
+	default:
+		return false, nil
+	}
 }
 
+// Cleanup supprime les zones de staging orphelines (celles d'un commit interrompu
+// sont conservées pour RecoverInterruptedInstalls)
 func (is *InstallerService) Cleanup() error {
-	matches, err := filepath.Glob(filepath.Join(is.TempDir, "TempDir", "install_*"))
+	matches, err := filepath.Glob(filepath.Join(is.TempDir, "install_*"))
 	if err != nil {
 		return err
 	}
 
 	for _, match := range matches {
+		if is.hasPendingJournal(match) {
+			continue
+		}
 		os.RemoveAll(match)
 	}
 	return nil
@@ -0,0 +1,174 @@
+// services/loadorder.go
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"mod-installer/config"
+	"mod-installer/models"
+)
+
+// LoadOrderService conserve l'ordre de chargement des mods. Les mods sont installés
+// dans cet ordre : quand deux mods écrivent le même fichier, le dernier l'emporte.
+// Sans choix de l'utilisateur, l'ordre suit la priorité donnée par le catalogue
+// (croissante), puis la clé du mod.
+type LoadOrderService struct {
+	path  string
+	order []string // Clés de mod (Mod.GroupKey) placées par l'utilisateur
+	mu    sync.Mutex
+}
+
+type loadOrderFile struct {
+	Order []string `json:"order"`
+}
+
+func NewLoadOrderService(cfg *config.Config) *LoadOrderService {
+	ls := &LoadOrderService{path: filepath.Join(cfg.CacheDir(), "load_order.json")}
+
+	data, err := os.ReadFile(ls.path)
+	if err != nil {
+		return ls
+	}
+	var saved loadOrderFile
+	if err := json.Unmarshal(data, &saved); err != nil {
+		fmt.Printf("Ordre de chargement illisible, ordre du catalogue utilisé: %v\n", err)
+		return ls
+	}
+	ls.order = saved.Order
+	return ls
+}
+
+// SortMods trie les mods dans l'ordre de chargement
+func (ls *LoadOrderService) SortMods(mods []models.Mod) {
+	priorities := make(map[string]int, len(mods))
+	keys := make([]string, len(mods))
+	for i := range mods {
+		keys[i] = mods[i].GroupKey()
+		priorities[keys[i]] = mods[i].Priority
+	}
+	rank := ls.rank(keys, priorities)
+	sort.SliceStable(mods, func(i, j int) bool { return rank[mods[i].GroupKey()] < rank[mods[j].GroupKey()] })
+}
+
+// SortGroups trie les mods du catalogue dans l'ordre de chargement, d'après la
+// priorité de leur version la plus récente
+func (ls *LoadOrderService) SortGroups(groups []models.ModGroup) {
+	priorities := make(map[string]int, len(groups))
+	keys := make([]string, len(groups))
+	for i, group := range groups {
+		keys[i] = group.Key()
+		if latest := group.Versions.Latest(); latest != nil {
+			priorities[keys[i]] = latest.Priority
+		}
+	}
+	rank := ls.rank(keys, priorities)
+	sort.SliceStable(groups, func(i, j int) bool { return rank[groups[i].Key()] < rank[groups[j].Key()] })
+}
+
+// Move déplace un mod de delta positions dans l'ordre affiché keys (négatif : plus
+// tôt) et enregistre l'ordre obtenu
+func (ls *LoadOrderService) Move(keys []string, key string, delta int) error {
+	from := -1
+	for i, k := range keys {
+		if k == key {
+			from = i
+		}
+	}
+	if from < 0 {
+		return fmt.Errorf("mod inconnu: %s", key)
+	}
+	to := from + delta
+	if to < 0 {
+		to = 0
+	}
+	if to >= len(keys) {
+		to = len(keys) - 1
+	}
+
+	order := make([]string, 0, len(keys))
+	order = append(order, keys[:from]...)
+	order = append(order, keys[from+1:]...)
+	order = append(order[:to], append([]string{key}, order[to:]...)...)
+
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+
+	// Les mods placés auparavant mais absents de keys (autre catalogue) sont conservés
+	shown := make(map[string]bool, len(order))
+	for _, k := range order {
+		shown[k] = true
+	}
+	for _, k := range ls.order {
+		if !shown[k] {
+			order = append(order, k)
+		}
+	}
+	ls.order = order
+	return ls.save()
+}
+
+// rank attribue une position à chaque clé : les clés placées par l'utilisateur
+// gardent leur ordre relatif, les autres sont insérées avant la première clé placée
+// de priorité strictement supérieure
+func (ls *LoadOrderService) rank(keys []string, priorities map[string]int) map[string]int {
+	ls.mu.Lock()
+	placed := make([]string, 0, len(ls.order))
+	isPlaced := make(map[string]bool, len(ls.order))
+	for _, key := range ls.order {
+		if _, known := priorities[key]; known && !isPlaced[key] {
+			placed = append(placed, key)
+			isPlaced[key] = true
+		}
+	}
+	ls.mu.Unlock()
+
+	others := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if !isPlaced[key] {
+			others = append(others, key)
+		}
+	}
+	sort.SliceStable(others, func(i, j int) bool {
+		if priorities[others[i]] != priorities[others[j]] {
+			return priorities[others[i]] < priorities[others[j]]
+		}
+		return others[i] < others[j]
+	})
+
+	order := make([]string, 0, len(keys))
+	next := 0
+	for _, key := range placed {
+		for next < len(others) && priorities[others[next]] < priorities[key] {
+			order = append(order, others[next])
+			next++
+		}
+		order = append(order, key)
+	}
+	order = append(order, others[next:]...)
+
+	rank := make(map[string]int, len(order))
+	for i, key := range order {
+		rank[key] = i
+	}
+	return rank
+}
+
+func (ls *LoadOrderService) save() error {
+	data, err := json.MarshalIndent(loadOrderFile{Order: ls.order}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(ls.path), 0755); err != nil {
+		return err
+	}
+	tmp := ls.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, ls.path)
+}
@@ -0,0 +1,92 @@
+// services/mediafire.go
+package services
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"html"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+var (
+	mediafireButtonRe    = regexp.MustCompile(`(?is)<a[^>]*id="downloadButton"[^>]*>`)
+	mediafireHrefRe      = regexp.MustCompile(`(?is)\bhref="([^"]*)"`)
+	mediafireScrambledRe = regexp.MustCompile(`(?is)\bdata-scrambled-url="([^"]*)"`)
+)
+
+// MediafireSource télécharge depuis les pages d'accueil de type Mediafire : la page
+// du fichier contient un bouton « Download » pointant vers un serveur de fichiers.
+type MediafireSource struct {
+	client *http.Client
+}
+
+func NewMediafireSource(client *http.Client) *MediafireSource {
+	return &MediafireSource{client: client}
+}
+
+func (s *MediafireSource) Name() string {
+	return "Mediafire"
+}
+
+func (s *MediafireSource) Matches(rawURL string) bool {
+	return hostMatches(rawURL, "mediafire.com")
+}
+
+func (s *MediafireSource) Resolve(ctx context.Context, rawURL string) (*ResolvedDownload, error) {
+	// Lien direct vers un serveur de fichiers (download1234.mediafire.com)
+	if parsed, err := url.Parse(rawURL); err == nil && strings.HasPrefix(parsed.Hostname(), "download") {
+		return fetchFile(ctx, s.client, rawURL)
+	}
+
+	page, pageURL, err := fetchPage(ctx, s.client, rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	link, err := parseMediafireLink(page)
+	if err != nil {
+		return nil, err
+	}
+	target, err := pageURL.Parse(link)
+	if err != nil {
+		return nil, fmt.Errorf("lien de téléchargement invalide: %w", err)
+	}
+
+	resolved, err := fetchFile(ctx, s.client, target.String())
+	if err != nil {
+		return nil, err
+	}
+	if isHTMLResponse(resolved.Response) {
+		resolved.Close()
+		return nil, fmt.Errorf("HTML reçu au lieu du fichier")
+	}
+	return resolved, nil
+}
+
+// parseMediafireLink retrouve le lien du bouton de téléchargement. Les pages récentes
+// le masquent en base64 dans data-scrambled-url.
+func parseMediafireLink(page string) (string, error) {
+	button := mediafireButtonRe.FindString(page)
+	if button == "" {
+		return "", fmt.Errorf("bouton de téléchargement introuvable (fichier supprimé ou privé ?)")
+	}
+
+	if href := mediafireHrefRe.FindStringSubmatch(button); href != nil {
+		link := html.UnescapeString(href[1])
+		if strings.HasPrefix(link, "http") {
+			return link, nil
+		}
+	}
+
+	if scrambled := mediafireScrambledRe.FindStringSubmatch(button); scrambled != nil {
+		decoded, err := base64.StdEncoding.DecodeString(html.UnescapeString(scrambled[1]))
+		if err == nil && strings.HasPrefix(string(decoded), "http") {
+			return string(decoded), nil
+		}
+	}
+	return "", fmt.Errorf("lien de téléchargement introuvable dans la page")
+}
@@ -0,0 +1,153 @@
+// services/mirrors.go
+package services
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"mod-installer/models"
+)
+
+// Nombre d'échecs consécutifs à partir duquel un miroir passe en fin de liste
+const mirrorDemoteThreshold = 3
+
+// MirrorHealth est l'historique d'un miroir, toutes mods confondus
+type MirrorHealth struct {
+	Successes   int       `json:"successes"`
+	Failures    int       `json:"failures"` // Échecs consécutifs depuis le dernier succès
+	LastSuccess time.Time `json:"last_success,omitempty"`
+	LastFailure time.Time `json:"last_failure,omitempty"`
+	LastError   string    `json:"last_error,omitempty"`
+}
+
+// Demoted indique si le miroir a échoué trop souvent d'affilée
+func (h MirrorHealth) Demoted() bool {
+	return h.Failures >= mirrorDemoteThreshold
+}
+
+// mirrorStats est enregistré dans le cache des téléchargements (mirrors.json)
+type mirrorStats struct {
+	Mirrors map[string]MirrorHealth `json:"mirrors"`
+	// Dernier miroir ayant servi chaque mod
+	LastGood map[string]string `json:"last_good"`
+
+	path string
+	mu   sync.Mutex
+}
+
+func loadMirrorStats(path string) *mirrorStats {
+	stats := &mirrorStats{path: path}
+	if data, err := os.ReadFile(path); err == nil {
+		json.Unmarshal(data, stats)
+	}
+	if stats.Mirrors == nil {
+		stats.Mirrors = make(map[string]MirrorHealth)
+	}
+	if stats.LastGood == nil {
+		stats.LastGood = make(map[string]string)
+	}
+	return stats
+}
+
+// order trie les miroirs du mod : celui qui a servi la dernière fois d'abord, les
+// miroirs rétrogradés à la fin, l'ordre du catalogue sinon
+func (s *mirrorStats) order(mod *models.Mod) []models.Mirror {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	mirrors := mod.DownloadMirrors()
+	lastGood := s.LastGood[mod.ID]
+	rank := func(m models.Mirror) int {
+		switch {
+		case s.Mirrors[m.URL].Demoted():
+			return 2
+		case m.URL == lastGood:
+			return 0
+		default:
+			return 1
+		}
+	}
+	sort.SliceStable(mirrors, func(i, j int) bool {
+		return rank(mirrors[i]) < rank(mirrors[j])
+	})
+	return mirrors
+}
+
+// lastGood retourne le miroir ayant servi le mod en dernier, s'il figure toujours au catalogue
+func (s *mirrorStats) lastGood(mod *models.Mod) (models.Mirror, bool) {
+	s.mu.Lock()
+	url := s.LastGood[mod.ID]
+	s.mu.Unlock()
+
+	for _, mirror := range mod.DownloadMirrors() {
+		if mirror.URL == url {
+			return mirror, true
+		}
+	}
+	return models.Mirror{}, false
+}
+
+func (s *mirrorStats) recordSuccess(modID, url string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	health := s.Mirrors[url]
+	health.Successes++
+	health.Failures = 0
+	health.LastSuccess = time.Now()
+	health.LastError = ""
+	s.Mirrors[url] = health
+	s.LastGood[modID] = url
+	s.save()
+}
+
+func (s *mirrorStats) recordFailure(url string, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	health := s.Mirrors[url]
+	health.Failures++
+	health.LastFailure = time.Now()
+	health.LastError = err.Error()
+	s.Mirrors[url] = health
+	s.save()
+}
+
+func (s *mirrorStats) health(url string) MirrorHealth {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.Mirrors[url]
+}
+
+// save doit être appelé avec le verrou tenu
+func (s *mirrorStats) save() {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return
+	}
+	tmp := s.path + ".tmp"
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return
+	}
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return
+	}
+	os.Rename(tmp, s.path)
+}
+
+// MirrorHealth retourne l'historique d'un miroir
+func (ds *DownloadService) MirrorHealth(url string) MirrorHealth {
+	return ds.mirrors.health(url)
+}
+
+// SuccessfulMirror retourne l'URL du miroir ayant servi le dernier téléchargement du mod
+func (ds *DownloadService) SuccessfulMirror(mod *models.Mod) string {
+	if mirror, ok := ds.mirrors.lastGood(mod); ok {
+		return mirror.URL
+	}
+	return ""
+}
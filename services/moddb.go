@@ -0,0 +1,100 @@
+// services/moddb.go
+package services
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+var (
+	moddbStartRe  = regexp.MustCompile(`(?is)href="([^"]*/downloads/start/\d+[^"]*)"`)
+	moddbMirrorRe = regexp.MustCompile(`(?is)href="([^"]*/downloads/mirror/\d+/[^"]*)"`)
+)
+
+// ModDBSource télécharge depuis ModDB : page du fichier, puis page « start » listant
+// les miroirs, essayés dans l'ordre jusqu'à ce que l'un d'eux serve le fichier.
+type ModDBSource struct {
+	client *http.Client
+}
+
+func NewModDBSource(client *http.Client) *ModDBSource {
+	return &ModDBSource{client: client}
+}
+
+func (s *ModDBSource) Name() string {
+	return "ModDB"
+}
+
+func (s *ModDBSource) Matches(rawURL string) bool {
+	return hostMatches(rawURL, "moddb.com")
+}
+
+func (s *ModDBSource) Resolve(ctx context.Context, rawURL string) (*ResolvedDownload, error) {
+	if strings.Contains(rawURL, "/downloads/mirror/") {
+		return s.fetchMirror(ctx, rawURL)
+	}
+
+	startURL := rawURL
+	if !strings.Contains(rawURL, "/downloads/start/") {
+		page, pageURL, err := fetchPage(ctx, s.client, rawURL)
+		if err != nil {
+			return nil, err
+		}
+		link := moddbStartRe.FindStringSubmatch(page)
+		if link == nil {
+			return nil, fmt.Errorf("lien de téléchargement introuvable dans la page")
+		}
+		target, err := pageURL.Parse(html.UnescapeString(link[1]))
+		if err != nil {
+			return nil, fmt.Errorf("lien de téléchargement invalide: %w", err)
+		}
+		startURL = target.String()
+	}
+
+	page, pageURL, err := fetchPage(ctx, s.client, startURL)
+	if err != nil {
+		return nil, err
+	}
+	mirrors := moddbMirrorRe.FindAllStringSubmatch(page, -1)
+	if len(mirrors) == 0 {
+		return nil, fmt.Errorf("aucun miroir de téléchargement dans la page")
+	}
+
+	lastErr := fmt.Errorf("aucun lien de miroir valide")
+	seen := make(map[string]bool)
+	for _, mirror := range mirrors {
+		target, err := pageURL.Parse(html.UnescapeString(mirror[1]))
+		if err != nil || seen[target.String()] {
+			continue
+		}
+		seen[target.String()] = true
+
+		resolved, err := s.fetchMirror(ctx, target.String())
+		if err == nil {
+			return resolved, nil
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		fmt.Printf("ModDB: miroir %s indisponible: %v\n", target, err)
+		lastErr = err
+	}
+	return nil, fmt.Errorf("aucun miroir disponible: %w", lastErr)
+}
+
+// fetchMirror suit la redirection d'un miroir vers le fichier
+func (s *ModDBSource) fetchMirror(ctx context.Context, mirrorURL string) (*ResolvedDownload, error) {
+	resolved, err := fetchFile(ctx, s.client, mirrorURL)
+	if err != nil {
+		return nil, err
+	}
+	if isHTMLResponse(resolved.Response) {
+		resolved.Close()
+		return nil, fmt.Errorf("HTML reçu au lieu du fichier")
+	}
+	return resolved, nil
+}
@@ -0,0 +1,276 @@
+// services/packconflicts.go
+package services
+
+import (
+	"fmt"
+	"io"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"mod-installer/models"
+	"mod-installer/utils"
+	"mod-installer/utils/ntw"
+)
+
+// PackSource est un fichier .pack dont l'index est comparé
+type PackSource struct {
+	Pack      string // Nom du fichier .pack dans data/
+	ModID     string // Vide pour un pack non installé par l'installeur
+	Name      string
+	Vanilla   bool // Pack du jeu (boot, release, patch, movie) non installé par un mod
+	Installed bool // Déjà dans le jeu (sinon prévu par l'installation en cours)
+}
+
+// owner identifie le propriétaire d'un pack : le mod, ou le pack lui-même
+func (s PackSource) owner() string {
+	if s.ModID != "" {
+		return s.ModID
+	}
+	return "pack:" + strings.ToLower(s.Pack)
+}
+
+// PackConflict est un chemin interne (table, texte, interface) fourni par plusieurs packs
+type PackConflict struct {
+	Path    string       // Chemin interne : db/units_tables/units
+	Sources []PackSource // Packs du jeu, puis mods installés, puis mods prévus
+}
+
+// OverridesVanilla indique si un pack du jeu fournit aussi ce chemin
+func (c PackConflict) OverridesVanilla() bool {
+	for _, source := range c.Sources {
+		if source.Vanilla {
+			return true
+		}
+	}
+	return false
+}
+
+// Mods retourne les packs de mods qui fournissent ce chemin
+func (c PackConflict) Mods() []PackSource {
+	mods := make([]PackSource, 0, len(c.Sources))
+	for _, source := range c.Sources {
+		if !source.Vanilla {
+			mods = append(mods, source)
+		}
+	}
+	return mods
+}
+
+// BetweenMods indique si au moins deux mods différents fournissent ce chemin
+func (c PackConflict) BetweenMods() bool {
+	return countOwners(c.Mods()) > 1
+}
+
+// PackTableConflict est une table de la base de données modifiée par plusieurs mods,
+// même sous des noms de fichiers différents
+type PackTableConflict struct {
+	Table   string // db/units_tables
+	Sources []PackSource
+}
+
+// PackConflictReport est le résultat de l'analyse du contenu des packs
+type PackConflictReport struct {
+	Files    []PackConflict      // Chemins fournis par un mod et au moins un autre pack
+	Tables   []PackTableConflict // Tables modifiées par plusieurs mods
+	Warnings []string            // Packs illisibles, ignorés
+}
+
+// ModConflicts retourne les chemins fournis par plusieurs mods
+func (r *PackConflictReport) ModConflicts() []PackConflict {
+	conflicts := make([]PackConflict, 0)
+	for _, conflict := range r.Files {
+		if conflict.BetweenMods() {
+			conflicts = append(conflicts, conflict)
+		}
+	}
+	return conflicts
+}
+
+// HasModConflicts indique si des mods se recouvrent entre eux, au-delà des fichiers du jeu
+func (r *PackConflictReport) HasModConflicts() bool {
+	return len(r.Tables) > 0 || len(r.ModConflicts()) > 0
+}
+
+// CheckPackConflicts compare l'index interne des packs de data/ (jeu et mods installés)
+// et des packs des mods prévus (archives[mod.ID], lus sans extraction). Les versions
+// installées d'un mod prévu ne sont pas comptées, comme pour CheckFileConflicts ; mods
+// peut être vide pour n'analyser que le jeu tel qu'il est installé.
+func (is *InstallerService) CheckPackConflicts(mods []models.Mod, archives map[string]string) (*PackConflictReport, error) {
+	report := &PackConflictReport{Files: make([]PackConflict, 0), Tables: make([]PackTableConflict, 0), Warnings: make([]string, 0)}
+	sources := make([]PackSource, 0)
+	indexes := make([]*ntw.Pack, 0)
+	add := func(source PackSource, pack *ntw.Pack) {
+		sources = append(sources, source)
+		indexes = append(indexes, pack)
+	}
+
+	planned := make(map[string]bool, len(mods)*2)
+	for i := range mods {
+		planned[mods[i].ID] = true
+		planned[mods[i].GroupKey()] = true
+	}
+
+	// Packs des mods prévus : lus en premier pour ignorer les fichiers qu'ils remplacent
+	plannedSources := make([]PackSource, 0)
+	plannedIndexes := make([]*ntw.Pack, 0)
+	replaced := make(map[string]bool) // Noms de packs (minuscules) réécrits par un mod prévu
+	for i := range mods {
+		archivePath, ok := archives[mods[i].ID]
+		if !ok {
+			return nil, fmt.Errorf("archive de %s non téléchargée", mods[i].Name)
+		}
+		err := utils.ReadArchiveEntries(archivePath, func(name string) bool {
+			dest, ok := entryDestination(&mods[i], name)
+			return ok && isDataPack(dest)
+		}, func(entry utils.ArchiveEntry, r io.Reader) error {
+			dest, _ := entryDestination(&mods[i], entry.Name)
+			name := strings.TrimPrefix(dest, models.InstallTargetData+"/")
+			replaced[strings.ToLower(name)] = true
+
+			pack, err := ntw.ReadPack(r)
+			if err != nil {
+				report.Warnings = append(report.Warnings, fmt.Sprintf("%s (%s): %v", name, mods[i].Name, err))
+				return nil
+			}
+			plannedSources = append(plannedSources, PackSource{Pack: name, ModID: mods[i].ID, Name: mods[i].Name})
+			plannedIndexes = append(plannedIndexes, pack)
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("lecture de l'archive de %s: %w", mods[i].Name, err)
+		}
+	}
+
+	// Packs installés par l'installeur
+	installed, err := is.InstalledMods()
+	if err != nil {
+		return nil, err
+	}
+	dataPath := filepath.Clean(is.GetDataPath())
+	owned := make(map[string]bool) // Packs de data/ appartenant à un mod installé
+	for _, manifest := range installed {
+		for _, file := range manifest.Files {
+			if filepath.Clean(file.Root) != dataPath || !isDataPack(file.Path) {
+				continue
+			}
+			name := filepath.ToSlash(file.Path)
+			owned[strings.ToLower(name)] = true
+			if planned[manifest.ModID] || (manifest.Group != "" && planned[manifest.Group]) || replaced[strings.ToLower(name)] {
+				continue
+			}
+			pack, err := ntw.ReadPackFile(file.FullPath())
+			if err != nil {
+				report.Warnings = append(report.Warnings, fmt.Sprintf("%s (%s): %v", name, manifest.Name, err))
+				continue
+			}
+			add(PackSource{Pack: name, ModID: manifest.ModID, Name: manifest.Name, Installed: true}, pack)
+		}
+	}
+
+	// Autres packs de data/ : ceux du jeu, et les mods copiés à la main (type mod)
+	gamePacks, err := filepath.Glob(filepath.Join(dataPath, "*.pack"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(gamePacks)
+	vanillaSources := make([]PackSource, 0)
+	vanillaIndexes := make([]*ntw.Pack, 0)
+	for _, packPath := range gamePacks {
+		name := filepath.Base(packPath)
+		if owned[strings.ToLower(name)] || replaced[strings.ToLower(name)] {
+			continue
+		}
+		pack, err := ntw.ReadPackFile(packPath)
+		if err != nil {
+			report.Warnings = append(report.Warnings, fmt.Sprintf("%s: %v", name, err))
+			continue
+		}
+		if pack.Type == ntw.PackMod {
+			add(PackSource{Pack: name, Name: name, Installed: true}, pack)
+			continue
+		}
+		vanillaSources = append(vanillaSources, PackSource{Pack: name, Name: name, Vanilla: true, Installed: true})
+		vanillaIndexes = append(vanillaIndexes, pack)
+	}
+
+	// Ordre de chargement : le jeu, puis les mods installés, puis les mods prévus
+	sources = append(append(vanillaSources, sources...), plannedSources...)
+	indexes = append(append(vanillaIndexes, indexes...), plannedIndexes...)
+
+	byPath := make(map[string][]PackSource)
+	paths := make(map[string]string) // Clé -> chemin tel qu'affiché
+	byTable := make(map[string][]PackSource)
+	for i, pack := range indexes {
+		tables := make(map[string]bool)
+		for _, file := range pack.Files {
+			key := ntw.PackPathKey(file.Path)
+			if _, ok := paths[key]; !ok {
+				paths[key] = file.Path
+			}
+			byPath[key] = appendSource(byPath[key], sources[i])
+			if table := dbTable(key); table != "" && !sources[i].Vanilla {
+				tables[table] = true
+			}
+		}
+		for table := range tables {
+			byTable[table] = appendSource(byTable[table], sources[i])
+		}
+	}
+
+	for key, pathSources := range byPath {
+		conflict := PackConflict{Path: paths[key], Sources: pathSources}
+		if len(conflict.Mods()) > 0 && countOwners(pathSources) > 1 {
+			report.Files = append(report.Files, conflict)
+		}
+	}
+	for table, tableSources := range byTable {
+		if countOwners(tableSources) > 1 {
+			report.Tables = append(report.Tables, PackTableConflict{Table: table, Sources: tableSources})
+		}
+	}
+	sort.Slice(report.Files, func(i, j int) bool { return report.Files[i].Path < report.Files[j].Path })
+	sort.Slice(report.Tables, func(i, j int) bool { return report.Tables[i].Table < report.Tables[j].Table })
+	return report, nil
+}
+
+// isDataPack indique si une destination ou un chemin relatif à data/ est un pack posé
+// directement dans data/, le seul endroit où le jeu les charge
+func isDataPack(dest string) bool {
+	dest = filepath.ToSlash(dest)
+	if !strings.EqualFold(path.Ext(dest), ".pack") {
+		return false
+	}
+	dest = strings.TrimPrefix(dest, models.InstallTargetData+"/")
+	return !strings.Contains(dest, "/")
+}
+
+// dbTable retourne la table d'un chemin interne db/<table>/<fichier>, ou ""
+func dbTable(key string) string {
+	parts := strings.Split(key, "/")
+	if len(parts) < 3 || parts[0] != "db" {
+		return ""
+	}
+	return parts[0] + "/" + parts[1]
+}
+
+// appendSource ajoute un pack une seule fois
+func appendSource(sources []PackSource, source PackSource) []PackSource {
+	for _, existing := range sources {
+		if existing.Pack == source.Pack && existing.ModID == source.ModID {
+			return sources
+		}
+	}
+	return append(sources, source)
+}
+
+// countOwners compte les mods ou packs distincts : un mod livré en plusieurs packs
+// ne se recouvre pas lui-même
+func countOwners(sources []PackSource) int {
+	owners := make(map[string]bool, len(sources))
+	for _, source := range sources {
+		owners[source.owner()] = true
+	}
+	return len(owners)
+}
@@ -0,0 +1,283 @@
+// services/queue.go
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"mod-installer/models"
+)
+
+// InstallEventCallback reçoit l'état d'un mod à chaque étape de la file. detail contient
+// le fichier en cours d'extraction le cas échéant.
+type InstallEventCallback func(state models.Installation, detail string)
+
+// InstallQueue télécharge plusieurs mods en parallèle et les installe un par un, dans
+// l'ordre donné
+type InstallQueue struct {
+	downloader    *DownloadService
+	installer     *InstallerService
+	maxConcurrent int
+	winners       map[string]string // Fichier en conflit (clé de fileKey) -> ID du mod qui l'écrit
+}
+
+func NewInstallQueue(downloader *DownloadService, installer *InstallerService, maxConcurrent int) *InstallQueue {
+	if maxConcurrent < 1 {
+		maxConcurrent = 1
+	}
+	return &InstallQueue{
+		downloader:    downloader,
+		installer:     installer,
+		maxConcurrent: maxConcurrent,
+	}
+}
+
+// SetFileWinners fixe, pour chaque fichier en conflit (FileConflict.Path), le mod
+// dont la copie doit rester en place ; les autres mods de la file ne l'écrivent pas
+func (q *InstallQueue) SetFileWinners(winners map[string]string) {
+	q.winners = make(map[string]string, len(winners))
+	for dest, modID := range winners {
+		q.winners[fileKey(dest)] = modID
+	}
+}
+
+// skippedFiles retourne les fichiers en conflit qu'un autre mod doit garder
+func (q *InstallQueue) skippedFiles(modID string) map[string]bool {
+	skip := make(map[string]bool)
+	for key, winner := range q.winners {
+		if winner != modID {
+			skip[key] = true
+		}
+	}
+	return skip
+}
+
+// Download télécharge les mods sans les installer, avec au plus maxConcurrent
+// transferts simultanés, et retourne le chemin de chaque archive par ID de mod
+func (q *InstallQueue) Download(ctx context.Context, mods []models.Mod, onEvent InstallEventCallback) (map[string]string, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var mu sync.Mutex
+	paths := make(map[string]string, len(mods))
+	var firstErr error
+
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, q.maxConcurrent)
+	for i := range mods {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			select {
+			case semaphore <- struct{}{}:
+				defer func() { <-semaphore }()
+			case <-ctx.Done():
+				return
+			}
+
+			state := models.Installation{ModID: mods[i].ID}
+			result := q.download(ctx, &mods[i], func(update func(*models.Installation)) {
+				mu.Lock()
+				update(&state)
+				current := state
+				mu.Unlock()
+				if onEvent != nil {
+					onEvent(current, "")
+				}
+			})
+
+			mu.Lock()
+			defer mu.Unlock()
+			if result.err != nil {
+				if firstErr == nil {
+					firstErr = fmt.Errorf("téléchargement de %s: %w", mods[i].Name, result.err)
+					cancel()
+				}
+				return
+			}
+			paths[mods[i].ID] = result.path
+		}(i)
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err := ctx.Err(); err != nil && len(paths) < len(mods) {
+		return nil, err
+	}
+	return paths, nil
+}
+
+type downloadResult struct {
+	path string
+	err  error
+}
+
+// Run télécharge les mods avec au plus maxConcurrent transferts simultanés et les
+// installe séquentiellement dans l'ordre de mods, chacun dès que son archive est prête.
+// Au premier échec, les téléchargements restants sont annulés et l'erreur est retournée.
+func (q *InstallQueue) Run(ctx context.Context, mods []models.Mod, onEvent InstallEventCallback) error {
+	return q.run(ctx, mods, nil, nil, onEvent)
+}
+
+// RunDownloaded installe comme Run des mods déjà téléchargés par Download, sans
+// repasser par le téléchargement ; archives associe à chaque ID de mod son archive
+func (q *InstallQueue) RunDownloaded(ctx context.Context, mods []models.Mod, archives map[string]string, onEvent InstallEventCallback) error {
+	if archives == nil {
+		archives = make(map[string]string)
+	}
+	return q.run(ctx, mods, archives, nil, onEvent)
+}
+
+// Update installe les nouvelles versions comme Run. L'ancienne version de chaque mod
+// n'est retirée qu'une fois la nouvelle installée : si l'installation échoue, elle
+// reste en place.
+func (q *InstallQueue) Update(ctx context.Context, updates []ModUpdate, onEvent InstallEventCallback) error {
+	mods := make([]models.Mod, len(updates))
+	replaces := make(map[string]*models.InstallManifest, len(updates))
+	for i, update := range updates {
+		mods[i] = update.Latest
+		replaces[update.Latest.ID] = update.Installed
+	}
+	return q.run(ctx, mods, nil, replaces, onEvent)
+}
+
+// run exécute la file. Si archives n'est pas nil, les mods ne sont pas téléchargés et
+// leur archive y est prise ; replaces associe à un mod la version installée qu'il remplace.
+func (q *InstallQueue) run(ctx context.Context, mods []models.Mod, archives map[string]string, replaces map[string]*models.InstallManifest, onEvent InstallEventCallback) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	states := make([]models.Installation, len(mods))
+	var mu sync.Mutex
+	emit := func(i int, update func(*models.Installation), detail string) {
+		mu.Lock()
+		update(&states[i])
+		state := states[i]
+		mu.Unlock()
+		if onEvent != nil {
+			onEvent(state, detail)
+		}
+	}
+
+	results := make([]chan downloadResult, len(mods))
+	for i := range mods {
+		results[i] = make(chan downloadResult, 1)
+		emit(i, func(s *models.Installation) {
+			*s = models.Installation{ModID: mods[i].ID, Status: models.StatusPending}
+		}, "")
+	}
+
+	// Téléchargements parallèles, démarrés dans l'ordre d'installation
+	var wg sync.WaitGroup
+	defer func() {
+		cancel()
+		wg.Wait()
+	}()
+
+	semaphore := make(chan struct{}, q.maxConcurrent)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := range mods {
+			if archives != nil {
+				if path, ok := archives[mods[i].ID]; ok {
+					results[i] <- downloadResult{path: path}
+				} else {
+					results[i] <- downloadResult{err: fmt.Errorf("archive non téléchargée")}
+				}
+				continue
+			}
+
+			select {
+			case semaphore <- struct{}{}:
+			case <-ctx.Done():
+				results[i] <- downloadResult{err: ctx.Err()}
+				continue
+			}
+
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				defer func() { <-semaphore }()
+				results[i] <- q.download(ctx, &mods[i], func(update func(*models.Installation)) {
+					emit(i, update, "")
+				})
+			}(i)
+		}
+	}()
+
+	// Installations séquentielles
+	for i := range mods {
+		var result downloadResult
+		select {
+		case result = <-results[i]:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+
+		if result.err != nil {
+			err := fmt.Errorf("téléchargement de %s: %w", mods[i].Name, result.err)
+			q.fail(emit, i, err)
+			return err
+		}
+
+		emit(i, func(s *models.Installation) {
+			s.Status = models.StatusInstalling
+			s.Progress = 0
+		}, "")
+
+		onFile := func(currentFile string, processed, total int) {
+			emit(i, func(s *models.Installation) {
+				if total > 0 {
+					s.Progress = float64(processed) / float64(total)
+				}
+			}, currentFile)
+		}
+		var err error
+		if old := replaces[mods[i].ID]; old != nil {
+			err = q.installer.replaceMod(ctx, old, &mods[i], result.path, q.skippedFiles(mods[i].ID), onFile)
+		} else {
+			err = q.installer.installMod(ctx, &mods[i], result.path, q.skippedFiles(mods[i].ID), onFile)
+		}
+		if err != nil {
+			err = fmt.Errorf("installation de %s: %w", mods[i].Name, err)
+			q.fail(emit, i, err)
+			return err
+		}
+
+		emit(i, func(s *models.Installation) {
+			s.Status = models.StatusCompleted
+			s.Progress = 1
+			s.FinishedAt = time.Now()
+		}, "")
+	}
+	return nil
+}
+
+func (q *InstallQueue) download(ctx context.Context, mod *models.Mod, emit func(func(*models.Installation))) downloadResult {
+	emit(func(s *models.Installation) {
+		s.Status = models.StatusDownloading
+		s.StartedAt = time.Now()
+	})
+
+	path, err := q.downloader.DownloadMod(ctx, mod, func(downloaded, total int64) {
+		if total > 0 {
+			emit(func(s *models.Installation) {
+				s.Progress = float64(downloaded) / float64(total)
+			})
+		}
+	})
+	return downloadResult{path: path, err: err}
+}
+
+func (q *InstallQueue) fail(emit func(int, func(*models.Installation), string), i int, err error) {
+	emit(i, func(s *models.Installation) {
+		s.Status = models.StatusFailed
+		s.Error = err.Error()
+		s.FinishedAt = time.Now()
+	}, "")
+}
@@ -0,0 +1,244 @@
+// services/resolver.go
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	"mod-installer/models"
+)
+
+// PlanStep est un mod du plan d'installation
+type PlanStep struct {
+	Mod        models.Mod
+	Dependency bool     // Ajouté parce qu'un autre mod du plan en dépend
+	RequiredBy []string // Noms des mods du plan qui en dépendent
+}
+
+// InstallPlan est la liste des mods à installer, chaque mod après ses dépendances
+type InstallPlan struct {
+	Steps []PlanStep
+}
+
+// Mods retourne les mods du plan dans l'ordre d'installation
+func (p *InstallPlan) Mods() []models.Mod {
+	mods := make([]models.Mod, len(p.Steps))
+	for i, step := range p.Steps {
+		mods[i] = step.Mod
+	}
+	return mods
+}
+
+// MissingDependencyError signale une dépendance introuvable dans le catalogue
+type MissingDependencyError struct {
+	ModID      string
+	Dependency string
+}
+
+func (e *MissingDependencyError) Error() string {
+	return fmt.Sprintf("dépendance %q de %s introuvable dans le catalogue", e.Dependency, e.ModID)
+}
+
+// ConflictError signale deux mods incompatibles. Installed indique que OtherID est
+// déjà installé.
+type ConflictError struct {
+	ModID     string
+	OtherID   string
+	Installed bool
+	Reason    string
+}
+
+func (e *ConflictError) Error() string {
+	other := e.OtherID
+	if e.Installed {
+		other += " (installé)"
+	}
+	return fmt.Sprintf("%s est incompatible avec %s: %s", e.ModID, other, e.Reason)
+}
+
+// CycleError signale des dépendances circulaires
+type CycleError struct {
+	Cycle []string // IDs des mods, le premier répété à la fin
+}
+
+func (e *CycleError) Error() string {
+	return fmt.Sprintf("dépendances circulaires: %s", strings.Join(e.Cycle, " → "))
+}
+
+// PlanInstall calcule le plan d'installation de la sélection pour le jeu courant
+func (is *InstallerService) PlanInstall(catalog models.ModCatalog, selected []models.Mod) (*InstallPlan, error) {
+	installed, err := is.InstalledMods()
+	if err != nil {
+		return nil, err
+	}
+	return ResolveInstallPlan(catalog, selected, installed)
+}
+
+// ResolveInstallPlan ordonne les mods sélectionnés après leurs dépendances et ajoute
+// celles qui ne sont ni sélectionnées ni installées. Une dépendance ou un conflit
+// désigne un mod par son ID (une version précise) ou par sa clé <jeu>_<mod> (toutes
+// versions ; la plus récente est alors installée). Les conflits déclarés, dans un
+// sens ou dans l'autre, avec un mod du plan ou déjà installé, sont refusés.
+func ResolveInstallPlan(catalog models.ModCatalog, selected []models.Mod, installed []*models.InstallManifest) (*InstallPlan, error) {
+	r := &resolver{
+		catalog:   catalog,
+		selected:  selected,
+		installed: installed,
+		planned:   make(map[string]int),
+		byGroup:   make(map[string]string),
+		visiting:  make(map[string]bool),
+	}
+
+	for _, mod := range selected {
+		if err := r.visit(mod, nil, ""); err != nil {
+			return nil, err
+		}
+	}
+	if err := r.checkConflicts(); err != nil {
+		return nil, err
+	}
+	return &r.plan, nil
+}
+
+type resolver struct {
+	catalog   models.ModCatalog
+	selected  []models.Mod
+	installed []*models.InstallManifest
+
+	plan     InstallPlan
+	planned  map[string]int    // ID -> index dans le plan
+	byGroup  map[string]string // Clé de mod -> ID de la version retenue
+	visiting map[string]bool
+}
+
+// visit ajoute mod au plan après ses dépendances (parcours en profondeur)
+func (r *resolver) visit(mod models.Mod, path []string, requiredBy string) error {
+	if i, done := r.planned[mod.ID]; done {
+		if requiredBy != "" {
+			r.plan.Steps[i].RequiredBy = append(r.plan.Steps[i].RequiredBy, requiredBy)
+		}
+		return nil
+	}
+
+	path = append(path, mod.ID)
+	if r.visiting[mod.ID] {
+		start := 0
+		for i, id := range path {
+			if id == mod.ID {
+				start = i
+				break
+			}
+		}
+		return &CycleError{Cycle: path[start:]}
+	}
+
+	// Une seule version d'un même mod par plan
+	if other, ok := r.byGroup[mod.GroupKey()]; ok && other != mod.ID {
+		return &ConflictError{ModID: mod.ID, OtherID: other, Reason: "deux versions du même mod"}
+	}
+
+	r.visiting[mod.ID] = true
+	for _, ref := range mod.Dependencies {
+		dependency, needed, err := r.resolveDependency(mod, ref)
+		if err != nil {
+			return err
+		}
+		if !needed {
+			continue
+		}
+		if err := r.visit(*dependency, path, mod.Name); err != nil {
+			return err
+		}
+	}
+	r.visiting[mod.ID] = false
+
+	step := PlanStep{Mod: mod, Dependency: !r.isSelected(mod.ID)}
+	if requiredBy != "" {
+		step.RequiredBy = []string{requiredBy}
+	}
+	r.planned[mod.ID] = len(r.plan.Steps)
+	r.byGroup[mod.GroupKey()] = mod.ID
+	r.plan.Steps = append(r.plan.Steps, step)
+	return nil
+}
+
+// resolveDependency choisit le mod qui satisfait ref : un mod sélectionné ou déjà
+// retenu dans le plan en priorité, sinon rien si un mod installé convient, sinon la
+// version du catalogue
+func (r *resolver) resolveDependency(mod models.Mod, ref string) (*models.Mod, bool, error) {
+	for i := range r.selected {
+		if modMatches(&r.selected[i], ref) {
+			return &r.selected[i], true, nil
+		}
+	}
+	if id, ok := r.byGroup[ref]; ok {
+		return &r.plan.Steps[r.planned[id]].Mod, true, nil
+	}
+	for _, manifest := range r.installed {
+		if manifestMatches(manifest, ref) {
+			return nil, false, nil
+		}
+	}
+	if dependency := r.catalog.Find(ref); dependency != nil {
+		return dependency, true, nil
+	}
+	for _, group := range r.catalog.Groups() {
+		if group.Key() == ref {
+			if latest := group.Versions.Latest(); latest != nil {
+				return latest, true, nil
+			}
+		}
+	}
+	return nil, false, &MissingDependencyError{ModID: mod.ID, Dependency: ref}
+}
+
+// checkConflicts vérifie les conflits déclarés entre les mods du plan et avec les
+// mods installés, y compris ceux déclarés par les mods installés d'après le catalogue
+func (r *resolver) checkConflicts() error {
+	for _, step := range r.plan.Steps {
+		mod := step.Mod
+		for _, ref := range mod.Conflicts {
+			for _, other := range r.plan.Steps {
+				if other.Mod.ID != mod.ID && modMatches(&other.Mod, ref) {
+					return &ConflictError{ModID: mod.ID, OtherID: other.Mod.ID, Reason: "conflit déclaré"}
+				}
+			}
+			for _, manifest := range r.installed {
+				if manifest.ModID != mod.ID && manifestMatches(manifest, ref) {
+					return &ConflictError{ModID: mod.ID, OtherID: manifest.ModID, Installed: true, Reason: "conflit déclaré"}
+				}
+			}
+		}
+
+		for _, manifest := range r.installed {
+			installedMod := r.catalog.Find(manifest.ModID)
+			if installedMod == nil || installedMod.ID == mod.ID {
+				continue
+			}
+			for _, ref := range installedMod.Conflicts {
+				if modMatches(&mod, ref) {
+					return &ConflictError{ModID: mod.ID, OtherID: manifest.ModID, Installed: true, Reason: "conflit déclaré par " + installedMod.Name}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (r *resolver) isSelected(id string) bool {
+	for _, mod := range r.selected {
+		if mod.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// modMatches indique si ref désigne mod (par son ID ou sa clé de mod)
+func modMatches(mod *models.Mod, ref string) bool {
+	return mod.ID == ref || mod.GroupKey() == ref
+}
+
+func manifestMatches(manifest *models.InstallManifest, ref string) bool {
+	return manifest.ModID == ref || (manifest.Group != "" && manifest.Group == ref)
+}
@@ -0,0 +1,92 @@
+// services/resume.go
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"mod-installer/models"
+)
+
+// partialDownload décrit un téléchargement interrompu, enregistré à côté du fichier
+// partiel pour pouvoir le reprendre avec une requête Range
+type partialDownload struct {
+	URL          string `json:"url"`
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"last_modified,omitempty"`
+	FileName     string `json:"file_name,omitempty"`
+	Total        int64  `json:"total"`
+}
+
+// validator retourne la valeur à envoyer dans If-Range. Un ETag faible ne peut pas
+// servir à valider une reprise.
+func (p *partialDownload) validator() string {
+	if p.ETag != "" && !strings.HasPrefix(p.ETag, "W/") {
+		return p.ETag
+	}
+	return p.LastModified
+}
+
+// getPartialPath retourne le fichier partiel stable associé à un mod
+func (ds *DownloadService) getPartialPath(mod *models.Mod) string {
+	return filepath.Join(ds.tempDir, fmt.Sprintf("download_%s.part", ds.generateCacheKey(mod)))
+}
+
+func partialMetaPath(partPath string) string {
+	return partPath + ".json"
+}
+
+func loadPartialDownload(partPath string) *partialDownload {
+	data, err := os.ReadFile(partialMetaPath(partPath))
+	if err != nil {
+		return nil
+	}
+	var partial partialDownload
+	if err := json.Unmarshal(data, &partial); err != nil {
+		return nil
+	}
+	return &partial
+}
+
+func (p *partialDownload) save(partPath string) error {
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(partialMetaPath(partPath), data, 0644)
+}
+
+// removePartial supprime le fichier partiel et ses métadonnées
+func removePartial(partPath string) {
+	os.Remove(partPath)
+	os.Remove(partialMetaPath(partPath))
+}
+
+// resumeOffset retourne la position à partir de laquelle reprendre, 0 si la reprise
+// n'est pas possible (pas de fichier partiel, URL différente ou aucun validateur)
+func resumeOffset(partPath, url string, partial *partialDownload) int64 {
+	if partial == nil || partial.URL != url || partial.validator() == "" {
+		return 0
+	}
+	info, err := os.Stat(partPath)
+	if err != nil {
+		return 0
+	}
+	return info.Size()
+}
+
+// contentRangeStart lit la position de début d'un en-tête "Content-Range: bytes a-b/c"
+func contentRangeStart(resp *http.Response) (int64, bool) {
+	value := strings.TrimPrefix(resp.Header.Get("Content-Range"), "bytes ")
+	dash := strings.Index(value, "-")
+	if dash <= 0 {
+		return 0, false
+	}
+	start, err := strconv.ParseInt(value[:dash], 10, 64)
+	return start, err == nil
+}
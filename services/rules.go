@@ -0,0 +1,96 @@
+// services/rules.go
+package services
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"mod-installer/models"
+	"mod-installer/utils"
+)
+
+// applyInstallRules replace les fichiers extraits selon les règles d'installation du
+// mod : chaque fichier est déplacé dans data/ ou scripts/ de la zone de staging, sous
+// son chemin relatif au dossier From de la première règle qui le couvre. Les fichiers
+// qu'aucune règle ne couvre (readme, captures...) sont écartés.
+//
+// Une destination peut être le chemin d'extraction d'un autre fichier : toutes les
+// destinations sont calculées et les fichiers écartés supprimés avant tout
+// déplacement, puis les fichiers retenus passent par un dossier intermédiaire.
+func applyInstallRules(stageDir string, written []utils.ExtractedFile, rules []models.InstallRule) ([]utils.ExtractedFile, error) {
+	if len(rules) == 0 {
+		return written, nil
+	}
+
+	placed := make([]utils.ExtractedFile, 0, len(written))
+	dests := make([]string, 0, len(written))
+	targets := make(map[string]string)
+	for _, file := range written {
+		name := path.Clean(strings.Trim(strings.ReplaceAll(file.Name, "\\", "/"), "/"))
+
+		rule, rel, ok := matchInstallRule(rules, name)
+		if !ok {
+			fmt.Printf("Fichier ignoré (aucune règle d'installation): %s\n", file.Name)
+			if err := os.Remove(file.Path); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		root := filepath.Join(stageDir, rule.To)
+		dest := filepath.Join(root, filepath.FromSlash(rel))
+		if other, exists := targets[dest]; exists {
+			return nil, fmt.Errorf("règles d'installation: %s et %s ont la même destination %s/%s", other, file.Name, rule.To, rel)
+		}
+		targets[dest] = file.Name
+
+		file.DestRoot = root
+		placed = append(placed, file)
+		dests = append(dests, dest)
+	}
+
+	// Premier déplacement vers des noms temporaires : aucun fichier retenu n'est écrasé
+	tmpDir, err := os.MkdirTemp(stageDir, "rules_")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	sources := make([]string, len(placed))
+	for i, file := range placed {
+		sources[i] = filepath.Join(tmpDir, fmt.Sprintf("%d", i))
+		if err := os.Rename(file.Path, sources[i]); err != nil {
+			return nil, fmt.Errorf("erreur placement de %s: %w", file.Name, err)
+		}
+	}
+	for i := range placed {
+		if err := os.MkdirAll(filepath.Dir(dests[i]), 0755); err != nil {
+			return nil, err
+		}
+		if err := os.Rename(sources[i], dests[i]); err != nil {
+			return nil, fmt.Errorf("erreur placement de %s: %w", placed[i].Name, err)
+		}
+		placed[i].Path = dests[i]
+	}
+	return placed, nil
+}
+
+// matchInstallRule retourne la première règle couvrant name et le chemin du fichier
+// relatif au dossier de la règle. Une règle désignant un fichier le place à la racine.
+func matchInstallRule(rules []models.InstallRule, name string) (models.InstallRule, string, bool) {
+	for _, rule := range rules {
+		from := strings.Trim(rule.From, "/")
+		switch {
+		case from == "" || from == ".":
+			return rule, name, true
+		case strings.EqualFold(name, from):
+			return rule, path.Base(name), true
+		case len(name) > len(from) && strings.EqualFold(name[:len(from)], from) && name[len(from)] == '/':
+			return rule, name[len(from)+1:], true
+		}
+	}
+	return models.InstallRule{}, "", false
+}
@@ -0,0 +1,169 @@
+// services/source.go
+package services
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// DownloadSource résout l'URL d'un mod, telle qu'écrite dans mods-meta, en flux de
+// téléchargement. Chaque hébergeur (Google Drive, Mediafire, ModDB...) a sa propre
+// source ; la source directe sert de repli pour les liens HTTP ordinaires.
+type DownloadSource interface {
+	// Name retourne le nom de l'hébergeur, pour les journaux et les erreurs
+	Name() string
+	// Matches indique si la source sait traiter cette URL
+	Matches(rawURL string) bool
+	// Resolve franchit les éventuelles pages intermédiaires et ouvre le fichier
+	Resolve(ctx context.Context, rawURL string) (*ResolvedDownload, error)
+}
+
+// ResolvedDownload est le fichier ouvert par une source
+type ResolvedDownload struct {
+	Body     io.ReadCloser
+	FileName string         // Nom annoncé par le serveur, "" si inconnu
+	Size     int64          // Taille du flux en octets, -1 si inconnue
+	Response *http.Response // Réponse finale (statut et en-têtes de reprise)
+}
+
+// Close ferme le flux du fichier
+func (r *ResolvedDownload) Close() error {
+	return r.Body.Close()
+}
+
+// RegisterSource ajoute une source, prioritaire sur les sources intégrées
+func (ds *DownloadService) RegisterSource(source DownloadSource) {
+	ds.sources = append([]DownloadSource{source}, ds.sources...)
+}
+
+// sourceFor retourne la première source qui accepte l'URL
+func (ds *DownloadService) sourceFor(rawURL string) (DownloadSource, error) {
+	for _, source := range ds.sources {
+		if source.Matches(rawURL) {
+			return source, nil
+		}
+	}
+	return nil, fmt.Errorf("aucune source de téléchargement pour %s", rawURL)
+}
+
+// defaultSources retourne les sources intégrées, la source directe en dernier
+func defaultSources(client *http.Client) []DownloadSource {
+	return []DownloadSource{
+		NewGoogleDriveSource(client),
+		NewMediafireSource(client),
+		NewModDBSource(client),
+		NewDirectSource(client),
+	}
+}
+
+// DirectSource télécharge un lien HTTP(S) tel quel
+type DirectSource struct {
+	client *http.Client
+}
+
+func NewDirectSource(client *http.Client) *DirectSource {
+	return &DirectSource{client: client}
+}
+
+func (s *DirectSource) Name() string {
+	return "HTTP"
+}
+
+func (s *DirectSource) Matches(rawURL string) bool {
+	parsed, err := url.Parse(rawURL)
+	return err == nil && (parsed.Scheme == "http" || parsed.Scheme == "https") && parsed.Host != ""
+}
+
+func (s *DirectSource) Resolve(ctx context.Context, rawURL string) (*ResolvedDownload, error) {
+	return fetchFile(ctx, s.client, rawURL)
+}
+
+// resumeKey porte dans le contexte la position de reprise d'un téléchargement
+type resumeKey struct{}
+
+type resumeRange struct {
+	offset    int64
+	validator string
+}
+
+// withResume demande aux sources de reprendre le fichier à offset (Range/If-Range)
+func withResume(ctx context.Context, offset int64, validator string) context.Context {
+	return context.WithValue(ctx, resumeKey{}, resumeRange{offset: offset, validator: validator})
+}
+
+// fetchFile ouvre le fichier d'une source en appliquant la reprise demandée via
+// withResume (Range/If-Range). Les pages connues à l'avance passent par fetchPage,
+// sans reprise ; mais une URL qui peut répondre par une page au lieu du fichier (page
+// d'avertissement Google Drive) reçoit aussi ces en-têtes, et le serveur renvoie alors
+// la page entière. Les statuts 206 et 416 sont laissés à l'appelant, qui gère la reprise.
+func fetchFile(ctx context.Context, client *http.Client, fileURL string) (*ResolvedDownload, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", fileURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	if resume, ok := ctx.Value(resumeKey{}).(resumeRange); ok && resume.offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", resume.offset))
+		req.Header.Set("If-Range", resume.validator)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusPartialContent, http.StatusRequestedRangeNotSatisfiable:
+	default:
+		resp.Body.Close()
+		return nil, fmt.Errorf("erreur HTTP: %s", resp.Status)
+	}
+
+	return &ResolvedDownload{
+		Body:     resp.Body,
+		FileName: responseFileName(resp),
+		Size:     resp.ContentLength,
+		Response: resp,
+	}, nil
+}
+
+// fetchPage lit une page intermédiaire (page d'accueil d'un hébergeur) et retourne
+// son contenu et son URL finale, pour résoudre les liens relatifs
+func fetchPage(ctx context.Context, client *http.Client, pageURL string) (string, *url.URL, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", pageURL, nil)
+	if err != nil {
+		return "", nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", nil, fmt.Errorf("erreur HTTP: %s", resp.Status)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 2*1024*1024))
+	if err != nil {
+		return "", nil, err
+	}
+	return string(body), resp.Request.URL, nil
+}
+
+// isHTMLResponse indique si le serveur a renvoyé une page web au lieu du fichier
+func isHTMLResponse(resp *http.Response) bool {
+	return strings.Contains(resp.Header.Get("Content-Type"), "text/html")
+}
+
+// hostMatches indique si l'hôte de l'URL est domain ou l'un de ses sous-domaines
+func hostMatches(rawURL, domain string) bool {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+	host := strings.ToLower(parsed.Hostname())
+	return host == domain || strings.HasSuffix(host, "."+domain)
+}
@@ -0,0 +1,417 @@
+// services/transaction.go
+package services
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"mod-installer/models"
+	"mod-installer/utils"
+)
+
+// journalOp est le déplacement d'un fichier de la zone de staging vers le jeu
+type journalOp struct {
+	Src     string `json:"src"`     // Fichier extrait dans la zone de staging
+	Dest    string `json:"dest"`    // Destination dans data/ ou scripts/
+	Root    string `json:"root"`    // Dossier racine de destination
+	Name    string `json:"name"`    // Nom de l'entrée dans l'archive
+	Existed bool   `json:"existed"` // La destination existait avant le commit
+}
+
+// installJournal décrit un commit d'installation en cours. Il est écrit en JSON lines :
+// la première ligne contient le plan, chaque ligne suivante l'index d'une opération terminée.
+type installJournal struct {
+	ModID       string      `json:"mod_id"`
+	Name        string      `json:"name"`
+	Version     string      `json:"version"`
+	Game        string      `json:"game,omitempty"`
+	Slug        string      `json:"slug,omitempty"`
+	ArchivePath string      `json:"archive_path"`
+	StageDir    string      `json:"stage_dir"`
+	BackupID    string      `json:"backup_id"`
+	KeepBackups bool        `json:"keep_backups"`
+	Ops         []journalOp `json:"ops"`
+
+	path string
+	done map[int]bool
+}
+
+type journalProgress struct {
+	Done int `json:"done"`
+}
+
+func (is *InstallerService) journalPath(modID string) string {
+	return filepath.Join(is.journalDir, models.ManifestFileName(modID)+"l")
+}
+
+// stageArchive extrait l'archive dans une zone de staging sous TempDir. Le format
+// est déterminé d'après le contenu du fichier, pas d'après son extension.
+func (is *InstallerService) stageArchive(ctx context.Context, mod *models.Mod, archivePath string, callback InstallProgressCallback) (string, []utils.ExtractedFile, error) {
+	extract, err := extractorForPath(archivePath)
+	if err != nil {
+		return "", nil, err
+	}
+
+	stageDir, err := os.MkdirTemp(is.TempDir, "install_")
+	if err != nil {
+		return "", nil, fmt.Errorf("erreur création zone de staging: %w", err)
+	}
+
+	written, err := extract(ctx, filepath.Join(stageDir, "scripts"), filepath.Join(stageDir, "data"), archivePath, callback)
+	if err != nil {
+		os.RemoveAll(stageDir)
+		return "", nil, err
+	}
+
+	// Les règles d'installation du catalogue priment sur le classement par extension
+	written, err = applyInstallRules(stageDir, written, mod.Installation)
+	if err != nil {
+		os.RemoveAll(stageDir)
+		return "", nil, err
+	}
+	return stageDir, written, nil
+}
+
+// extractorForPath choisit l'extracteur d'après le contenu du fichier ; un dossier
+// (téléchargement d'un dossier Google Drive) est copié tel quel
+func extractorForPath(archivePath string) (utils.Extractor, error) {
+	if info, err := os.Stat(archivePath); err == nil && info.IsDir() {
+		return utils.ExtractDirectory, nil
+	}
+
+	format, err := utils.DetectArchiveFormat(archivePath)
+	if err != nil {
+		return nil, err
+	}
+	extract, err := utils.ExtractorFor(format)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", err, filepath.Base(archivePath))
+	}
+	return extract, nil
+}
+
+// validateStage vérifie le contenu extrait avant de toucher au dossier du jeu
+func validateStage(stageDir string, written []utils.ExtractedFile) error {
+	if len(written) == 0 {
+		return fmt.Errorf("l'archive ne contient aucun fichier")
+	}
+
+	for _, file := range written {
+		if !strings.HasPrefix(filepath.Clean(file.Path), filepath.Clean(stageDir)+string(os.PathSeparator)) {
+			return fmt.Errorf("fichier extrait hors de la zone de staging: %s", file.Name)
+		}
+		info, err := os.Lstat(file.Path)
+		if err != nil {
+			return fmt.Errorf("fichier extrait introuvable %s: %w", file.Name, err)
+		}
+		if !info.Mode().IsRegular() {
+			return fmt.Errorf("type de fichier non supporté dans l'archive: %s", file.Name)
+		}
+	}
+	return nil
+}
+
+// planCommit associe chaque fichier extrait à sa destination réelle
+func (is *InstallerService) planCommit(mod *models.Mod, archivePath, stageDir string, written []utils.ExtractedFile) (*installJournal, error) {
+	roots := map[string]string{
+		filepath.Join(stageDir, "data"):    is.GetDataPath(),
+		filepath.Join(stageDir, "scripts"): is.GetScriptsPath(),
+	}
+
+	backups := newBackupSet(is.backupDir, mod.ID)
+	journal := &installJournal{
+		ModID:       mod.ID,
+		Name:        mod.Name,
+		Version:     mod.Version,
+		Game:        mod.Game,
+		Slug:        mod.Slug,
+		ArchivePath: archivePath,
+		StageDir:    stageDir,
+		BackupID:    backups.ID(),
+		KeepBackups: is.createBackups,
+		Ops:         make([]journalOp, 0, len(written)),
+		path:        is.journalPath(mod.ID),
+		done:        make(map[int]bool),
+	}
+
+	for _, file := range written {
+		root, ok := roots[file.DestRoot]
+		if !ok {
+			return nil, fmt.Errorf("destination inconnue pour %s", file.Name)
+		}
+		relPath, err := utils.GetRelativePath(file.DestRoot, file.Path)
+		if err != nil {
+			return nil, err
+		}
+		dest := filepath.Join(root, relPath)
+		journal.Ops = append(journal.Ops, journalOp{
+			Src:     file.Path,
+			Dest:    dest,
+			Root:    root,
+			Name:    file.Name,
+			Existed: utils.FileExists(dest),
+		})
+	}
+	return journal, nil
+}
+
+// commit déplace les fichiers de la zone de staging vers le jeu en journalisant chaque
+// étape. En cas d'erreur ou d'annulation, les opérations déjà faites sont annulées.
+func (is *InstallerService) commit(ctx context.Context, journal *installJournal) error {
+	if err := os.MkdirAll(is.journalDir, 0755); err != nil {
+		return err
+	}
+	header, err := json.Marshal(journal)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(journal.path, append(header, '\n'), 0644); err != nil {
+		return fmt.Errorf("erreur écriture du journal: %w", err)
+	}
+
+	log, err := os.OpenFile(journal.path, os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+
+	backups := &BackupSet{
+		dir:   filepath.Join(is.backupDir, journal.BackupID),
+		index: BackupIndex{ID: journal.BackupID, ModID: journal.ModID},
+		saved: make(map[string]bool),
+	}
+
+	err = is.applyOps(ctx, journal, backups, log)
+	log.Close()
+	if err != nil {
+		if rbErr := is.rollback(journal); rbErr != nil {
+			return fmt.Errorf("%v (annulation incomplète: %v)", err, rbErr)
+		}
+		return err
+	}
+	return is.finishCommit(journal)
+}
+
+func (is *InstallerService) applyOps(ctx context.Context, journal *installJournal, backups *BackupSet, log *os.File) error {
+	for i, op := range journal.Ops {
+		if journal.done[i] {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		if op.Existed {
+			if err := backups.Backup(op.Dest); err != nil {
+				return fmt.Errorf("erreur sauvegarde %s: %w", op.Dest, err)
+			}
+		}
+		if err := utils.MoveFile(op.Src, op.Dest); err != nil {
+			return fmt.Errorf("erreur installation %s: %w", op.Name, err)
+		}
+
+		journal.done[i] = true
+		line, _ := json.Marshal(journalProgress{Done: i})
+		if _, err := log.Write(append(line, '\n')); err != nil {
+			return fmt.Errorf("erreur écriture du journal: %w", err)
+		}
+	}
+	return nil
+}
+
+// finishCommit enregistre le manifeste puis nettoie le journal et la zone de staging
+func (is *InstallerService) finishCommit(journal *installJournal) error {
+	written := make([]utils.ExtractedFile, 0, len(journal.Ops))
+	backupID := ""
+	for _, op := range journal.Ops {
+		written = append(written, utils.ExtractedFile{
+			Name:     op.Name,
+			DestRoot: op.Root,
+			Path:     op.Dest,
+			Replaced: op.Existed,
+		})
+		if op.Existed && journal.KeepBackups {
+			backupID = journal.BackupID
+		}
+	}
+
+	mod := &models.Mod{ID: journal.ModID, Name: journal.Name, Version: journal.Version, Game: journal.Game, Slug: journal.Slug}
+	if err := is.recordInstallation(mod, journal.ArchivePath, backupID, written); err != nil {
+		return fmt.Errorf("mod installé mais manifeste non enregistré: %w", err)
+	}
+
+	if !journal.KeepBackups {
+		is.DeleteBackupSet(journal.BackupID)
+	}
+	os.RemoveAll(journal.StageDir)
+	return os.Remove(journal.path)
+}
+
+// rollback annule les opérations appliquées : les fichiers écrasés sont restaurés
+// depuis la sauvegarde, les fichiers créés sont supprimés
+func (is *InstallerService) rollback(journal *installJournal) error {
+	backups, _ := loadBackupSet(is.backupDir, journal.BackupID)
+
+	var firstErr error
+	for i := len(journal.Ops) - 1; i >= 0; i-- {
+		op := journal.Ops[i]
+		if !journal.applied(i, backups) {
+			continue
+		}
+
+		if op.Existed {
+			backupPath := ""
+			if backups != nil {
+				backupPath = backups.Lookup(op.Dest)
+			}
+			if backupPath == "" {
+				if firstErr == nil {
+					firstErr = fmt.Errorf("aucune sauvegarde pour restaurer %s", op.Dest)
+				}
+				continue
+			}
+			if err := utils.CopyFile(backupPath, op.Dest); err != nil && firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+
+		if err := os.Remove(op.Dest); err != nil && !os.IsNotExist(err) && firstErr == nil {
+			firstErr = err
+		}
+		pruneEmptyDirs(filepath.Dir(op.Dest), op.Root)
+	}
+
+	if firstErr != nil {
+		// Le journal est conservé pour une nouvelle tentative au prochain lancement
+		return firstErr
+	}
+
+	is.DeleteBackupSet(journal.BackupID)
+	os.RemoveAll(journal.StageDir)
+	return os.Remove(journal.path)
+}
+
+// applied indique si l'opération i a été appliquée, y compris lorsqu'une interruption
+// est survenue entre le déplacement du fichier et l'écriture dans le journal. Un fichier
+// du jeu écrasé est toujours sauvegardé avant le déplacement : sans sauvegarde, la
+// destination est encore celle d'origine, même si la zone de staging a disparu.
+func (j *installJournal) applied(i int, backups *BackupSet) bool {
+	if j.done[i] {
+		return true
+	}
+	op := j.Ops[i]
+	if op.Existed && (backups == nil || backups.Lookup(op.Dest) == "") {
+		return false
+	}
+	return !utils.FileExists(op.Src) && utils.FileExists(op.Dest)
+}
+
+func loadJournal(path string) (*installJournal, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 64*1024*1024)
+	if !scanner.Scan() {
+		return nil, fmt.Errorf("journal vide: %s", path)
+	}
+
+	journal := &installJournal{path: path, done: make(map[int]bool)}
+	if err := json.Unmarshal(scanner.Bytes(), journal); err != nil {
+		return nil, fmt.Errorf("journal corrompu %s: %w", path, err)
+	}
+
+	for scanner.Scan() {
+		var progress journalProgress
+		if err := json.Unmarshal(scanner.Bytes(), &progress); err != nil {
+			break // dernière ligne tronquée par l'interruption
+		}
+		journal.done[progress.Done] = true
+	}
+	return journal, nil
+}
+
+// RecoverInterruptedInstalls termine ou annule les commits interrompus lors d'un
+// lancement précédent. Un commit est terminé si tous les fichiers restants sont encore
+// présents dans la zone de staging, sinon il est annulé. Retourne les IDs des mods traités.
+func (is *InstallerService) RecoverInterruptedInstalls() ([]string, error) {
+	matches, err := filepath.Glob(filepath.Join(is.journalDir, "*.jsonl"))
+	if err != nil {
+		return nil, err
+	}
+
+	recovered := make([]string, 0, len(matches))
+	for _, match := range matches {
+		journal, err := loadJournal(match)
+		if err != nil {
+			return recovered, err
+		}
+
+		backups, _ := loadBackupSet(is.backupDir, journal.BackupID)
+		canFinish := true
+		for i, op := range journal.Ops {
+			if !journal.applied(i, backups) && !utils.FileExists(op.Src) {
+				canFinish = false
+				break
+			}
+		}
+
+		if canFinish {
+			fmt.Printf("Reprise de l'installation interrompue de %s\n", journal.ModID)
+			for i := range journal.Ops {
+				if journal.applied(i, backups) {
+					journal.done[i] = true
+				}
+			}
+			if backups == nil {
+				backups = &BackupSet{
+					dir:   filepath.Join(is.backupDir, journal.BackupID),
+					index: BackupIndex{ID: journal.BackupID, ModID: journal.ModID},
+					saved: make(map[string]bool),
+				}
+			}
+
+			log, err := os.OpenFile(journal.path, os.O_WRONLY|os.O_APPEND, 0644)
+			if err != nil {
+				return recovered, err
+			}
+			err = is.applyOps(context.Background(), journal, backups, log)
+			log.Close()
+			if err == nil {
+				err = is.finishCommit(journal)
+			}
+			if err != nil {
+				return recovered, fmt.Errorf("reprise de %s impossible: %w", journal.ModID, err)
+			}
+		} else {
+			fmt.Printf("Annulation de l'installation interrompue de %s\n", journal.ModID)
+			if err := is.rollback(journal); err != nil {
+				return recovered, fmt.Errorf("annulation de %s impossible: %w", journal.ModID, err)
+			}
+		}
+		recovered = append(recovered, journal.ModID)
+	}
+	return recovered, nil
+}
+
+// hasPendingJournal indique si stageDir appartient à un commit interrompu
+func (is *InstallerService) hasPendingJournal(stageDir string) bool {
+	matches, _ := filepath.Glob(filepath.Join(is.journalDir, "*.jsonl"))
+	for _, match := range matches {
+		if journal, err := loadJournal(match); err == nil && filepath.Clean(journal.StageDir) == filepath.Clean(stageDir) {
+			return true
+		}
+	}
+	return false
+}
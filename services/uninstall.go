@@ -0,0 +1,179 @@
+// services/uninstall.go
+package services
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"mod-installer/models"
+	"mod-installer/utils"
+)
+
+// UninstallReport résume les opérations effectuées par UninstallMod
+type UninstallReport struct {
+	Removed  []string // Fichiers supprimés
+	Restored []string // Fichiers écrasés à l'installation puis restaurés
+	Kept     []string // Fichiers conservés (partagés ou sans sauvegarde)
+	Missing  []string // Fichiers déjà absents du disque
+	Warnings []string
+}
+
+// SharedFilesError signale des fichiers du mod encore revendiqués par d'autres mods installés
+type SharedFilesError struct {
+	ModID  string
+	Owners map[string][]string // fichier -> IDs des autres mods
+}
+
+func (e *SharedFilesError) Error() string {
+	files := make([]string, 0, len(e.Owners))
+	for file, owners := range e.Owners {
+		files = append(files, fmt.Sprintf("%s (%s)", file, strings.Join(owners, ", ")))
+	}
+	sort.Strings(files)
+	return fmt.Sprintf("%d fichier(s) de %s utilisé(s) par d'autres mods:\n%s",
+		len(e.Owners), e.ModID, strings.Join(files, "\n"))
+}
+
+// UninstallMod supprime les fichiers écrits par un mod d'après son manifeste et
+// restaure ceux qu'il avait écrasés. Si d'autres mods installés revendiquent encore
+// certains fichiers, la désinstallation est refusée avec une *SharedFilesError, sauf
+// si force est vrai : ces fichiers sont alors conservés et signalés dans le rapport.
+func (is *InstallerService) UninstallMod(mod *models.Mod, force bool) (*UninstallReport, error) {
+	manifest, err := is.GetInstallManifest(mod)
+	if err != nil {
+		return nil, err
+	}
+	if manifest == nil {
+		return nil, fmt.Errorf("aucun manifeste d'installation pour %s", mod.ID)
+	}
+	return is.uninstallManifest(manifest, force)
+}
+
+// uninstallManifest retire les fichiers de manifest puis supprime son enregistrement
+func (is *InstallerService) uninstallManifest(manifest *models.InstallManifest, force bool) (*UninstallReport, error) {
+	owners, err := is.findOtherOwners(manifest)
+	if err != nil {
+		return nil, err
+	}
+	if len(owners) > 0 && !force {
+		return nil, &SharedFilesError{ModID: manifest.ModID, Owners: owners}
+	}
+
+	report := &UninstallReport{}
+	for _, file := range manifest.Files {
+		fullPath := file.FullPath()
+
+		if others, shared := owners[fullPath]; shared {
+			report.Kept = append(report.Kept, fullPath)
+			report.Warnings = append(report.Warnings,
+				fmt.Sprintf("%s conservé: utilisé par %s", fullPath, strings.Join(others, ", ")))
+			continue
+		}
+
+		if file.Replaced {
+			backupPath := is.findBackup(manifest, file)
+			if backupPath == "" {
+				report.Kept = append(report.Kept, fullPath)
+				report.Warnings = append(report.Warnings,
+					fmt.Sprintf("%s conservé: fichier d'origine écrasé sans sauvegarde", fullPath))
+				continue
+			}
+			if err := utils.CopyFile(backupPath, fullPath); err != nil {
+				return report, fmt.Errorf("erreur restauration %s: %w", fullPath, err)
+			}
+			report.Restored = append(report.Restored, fullPath)
+			continue
+		}
+
+		if err := os.Remove(fullPath); err != nil {
+			if os.IsNotExist(err) {
+				report.Missing = append(report.Missing, fullPath)
+				continue
+			}
+			return report, fmt.Errorf("erreur suppression %s: %w", fullPath, err)
+		}
+		report.Removed = append(report.Removed, fullPath)
+		pruneEmptyDirs(filepath.Dir(fullPath), file.Root)
+	}
+
+	if err := models.RemoveInstallManifest(is.manifestDir, manifest.ModID); err != nil {
+		return report, fmt.Errorf("erreur suppression du manifeste: %w", err)
+	}
+
+	// Le jeu de sauvegardes n'a plus d'utilité une fois tout restauré
+	if manifest.BackupID != "" && len(report.Kept) == 0 {
+		if err := is.DeleteBackupSet(manifest.BackupID); err != nil {
+			report.Warnings = append(report.Warnings, fmt.Sprintf("sauvegarde %s non supprimée: %v", manifest.BackupID, err))
+		}
+	}
+
+	fmt.Printf("Mod %s désinstallé: %d supprimé(s), %d restauré(s), %d conservé(s)\n",
+		manifest.ModID, len(report.Removed), len(report.Restored), len(report.Kept))
+	return report, nil
+}
+
+// findOtherOwners retourne, pour chaque fichier du manifeste, les autres mods installés
+// dans le même jeu qui l'ont aussi écrit
+func (is *InstallerService) findOtherOwners(manifest *models.InstallManifest) (map[string][]string, error) {
+	manifests, err := models.ListInstallManifests(is.manifestDir)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make(map[string]bool, len(manifest.Files))
+	for _, file := range manifest.Files {
+		files[filepath.Clean(file.FullPath())] = true
+	}
+
+	owners := make(map[string][]string)
+	for _, other := range manifests {
+		if other.ModID == manifest.ModID || filepath.Clean(other.GamePath) != filepath.Clean(manifest.GamePath) {
+			continue
+		}
+		for _, file := range other.Files {
+			fullPath := filepath.Clean(file.FullPath())
+			if files[fullPath] {
+				owners[fullPath] = append(owners[fullPath], other.ModID)
+			}
+		}
+	}
+	return owners, nil
+}
+
+// findBackup cherche une copie du fichier tel qu'il était avant l'installation.
+// Retourne "" si aucune sauvegarde n'est disponible.
+func (is *InstallerService) findBackup(manifest *models.InstallManifest, file models.InstalledFile) string {
+	// Sauvegarde faite juste avant l'écrasement
+	if manifest.BackupID != "" {
+		if bs, err := loadBackupSet(is.backupDir, manifest.BackupID); err == nil {
+			if backupPath := bs.Lookup(file.FullPath()); backupPath != "" {
+				return backupPath
+			}
+		}
+	}
+
+	relPath, err := utils.GetRelativePath(manifest.GamePath, file.FullPath())
+	if err != nil {
+		return ""
+	}
+
+	// Fichiers vanilla sauvegardés par VanillaService
+	vanillaBackup := filepath.Join(is.vanillaDir, strings.ReplaceAll(relPath, string(os.PathSeparator), "_"))
+	if utils.FileExists(vanillaBackup) {
+		return vanillaBackup
+	}
+	return ""
+}
+
+// pruneEmptyDirs supprime les dossiers vides de dir jusqu'à root (exclu)
+func pruneEmptyDirs(dir, root string) {
+	root = filepath.Clean(root)
+	for dir = filepath.Clean(dir); dir != root && strings.HasPrefix(dir, root+string(os.PathSeparator)); dir = filepath.Dir(dir) {
+		if err := os.Remove(dir); err != nil {
+			return // dossier non vide ou inaccessible
+		}
+	}
+}
@@ -0,0 +1,229 @@
+// services/updates.go
+package services
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"mod-installer/models"
+)
+
+// ModUpdate est une version plus récente d'un mod installé
+type ModUpdate struct {
+	Installed *models.InstallManifest // Enregistrement de la version installée
+	Latest    models.Mod              // Version proposée par le catalogue
+}
+
+// InstalledMods retourne les enregistrements des mods installés dans le jeu courant
+func (is *InstallerService) InstalledMods() ([]*models.InstallManifest, error) {
+	manifests, err := models.ListInstallManifests(is.manifestDir)
+	if err != nil {
+		return nil, err
+	}
+	installed := make([]*models.InstallManifest, 0, len(manifests))
+	for _, manifest := range manifests {
+		if filepath.Clean(manifest.GamePath) == filepath.Clean(is.gamePath) {
+			installed = append(installed, manifest)
+		}
+	}
+	return installed, nil
+}
+
+// AvailableUpdates compare les mods installés dans le jeu courant avec le catalogue
+func (is *InstallerService) AvailableUpdates(catalog models.ModCatalog) ([]ModUpdate, error) {
+	installed, err := is.InstalledMods()
+	if err != nil {
+		return nil, err
+	}
+	return FindUpdates(catalog, installed), nil
+}
+
+// FindUpdates retourne, pour chaque mod installé, la version la plus récente du
+// catalogue si elle est plus récente que celle installée. Une préversion installée
+// peut être mise à jour vers une préversion plus récente ; sinon seules les versions
+// finales sont proposées.
+func FindUpdates(catalog models.ModCatalog, installed []*models.InstallManifest) []ModUpdate {
+	groups := make(map[string]models.ModVersions)
+	for _, group := range catalog.Groups() {
+		groups[group.Key()] = group.Versions
+	}
+
+	updates := make([]ModUpdate, 0)
+	for _, manifest := range installed {
+		// Les installations antérieures au regroupement n'enregistrent que l'ID
+		groupKey := manifest.Group
+		if groupKey == "" {
+			mod := catalog.Find(manifest.ModID)
+			if mod == nil {
+				continue
+			}
+			groupKey = mod.GroupKey()
+		}
+
+		versions := groups[groupKey]
+		latest := versions.Latest()
+		if models.IsPrerelease(manifest.Version) && len(versions) > 0 {
+			latest = &versions[0]
+		}
+		if latest == nil || latest.ID == manifest.ModID {
+			continue
+		}
+		if models.CompareVersions(latest.Version, manifest.Version) > 0 {
+			updates = append(updates, ModUpdate{Installed: manifest, Latest: *latest})
+		}
+	}
+
+	sort.Slice(updates, func(i, j int) bool { return updates[i].Latest.ID < updates[j].Latest.ID })
+	return updates
+}
+
+// CatalogChanges résume les différences entre deux chargements du catalogue
+type CatalogChanges struct {
+	NewMods     []models.Mod // Mods absents du catalogue précédent
+	NewVersions []models.Mod // Nouvelles versions de mods déjà connus
+	Removed     []models.Mod // Versions retirées du catalogue
+}
+
+// Empty indique si le catalogue n'a pas changé
+func (c CatalogChanges) Empty() bool {
+	return len(c.NewMods) == 0 && len(c.NewVersions) == 0 && len(c.Removed) == 0
+}
+
+// DiffCatalogs compare deux catalogues à plat, indexés par clé de mod
+func DiffCatalogs(previous, current map[string]models.Mod) CatalogChanges {
+	knownGroups := make(map[string]bool, len(previous))
+	for _, mod := range previous {
+		knownGroups[mod.GroupKey()] = true
+	}
+
+	var changes CatalogChanges
+	for key, mod := range current {
+		if _, existed := previous[key]; existed {
+			continue
+		}
+		if knownGroups[mod.GroupKey()] {
+			changes.NewVersions = append(changes.NewVersions, mod)
+		} else {
+			changes.NewMods = append(changes.NewMods, mod)
+		}
+	}
+	for key, mod := range previous {
+		if _, exists := current[key]; !exists {
+			changes.Removed = append(changes.Removed, mod)
+		}
+	}
+
+	for _, mods := range [][]models.Mod{changes.NewMods, changes.NewVersions, changes.Removed} {
+		sort.Slice(mods, func(i, j int) bool { return mods[i].ID < mods[j].ID })
+	}
+	return changes
+}
+
+// replaceMod installe mod à la place de la version old. La nouvelle version est
+// installée d'abord : si elle échoue ou est annulée, le commit est annulé et old reste
+// en place. Ensuite seulement, old est retirée : ses fichiers repris par la nouvelle
+// version sont considérés comme écrasés, les autres sont désinstallés.
+func (is *InstallerService) replaceMod(ctx context.Context, old *models.InstallManifest, mod *models.Mod, archivePath string, skip map[string]bool, callback InstallProgressCallback) error {
+	if err := is.installMod(ctx, mod, archivePath, skip, callback); err != nil {
+		return err
+	}
+	if err := is.retireVersion(old, mod); err != nil {
+		return fmt.Errorf("version %s installée, mais retrait de la version %s: %w", mod.Version, old.Version, err)
+	}
+	return nil
+}
+
+// retireVersion retire la version old une fois mod installé. Pour les fichiers écrits
+// par les deux versions, le manifeste de mod reprend l'état d'origine connu de old
+// (fichier du jeu écrasé ou non, et sa sauvegarde) : la désinstaller plus tard
+// restaurera le jeu, pas l'ancienne version.
+func (is *InstallerService) retireVersion(old *models.InstallManifest, mod *models.Mod) error {
+	manifest, err := is.GetInstallManifest(mod)
+	if err != nil {
+		return err
+	}
+	if manifest == nil {
+		return fmt.Errorf("aucun manifeste d'installation pour %s", mod.ID)
+	}
+
+	oldFiles := make(map[string]models.InstalledFile, len(old.Files))
+	for _, file := range old.Files {
+		oldFiles[filepath.Clean(file.FullPath())] = file
+	}
+
+	// Les sauvegardes de la nouvelle installation contiennent l'ancienne version des
+	// fichiers partagés : elles sont remplacées par celles de l'état d'origine
+	var backups *BackupSet
+	if manifest.BackupID != "" {
+		backups, err = loadBackupSet(is.backupDir, manifest.BackupID)
+		if err != nil {
+			return err
+		}
+	}
+
+	remaining := make(map[string]bool, len(manifest.Files))
+	for i := range manifest.Files {
+		fullPath := filepath.Clean(manifest.Files[i].FullPath())
+		oldFile, shared := oldFiles[fullPath]
+		if !shared {
+			continue
+		}
+		remaining[fullPath] = true
+		manifest.Files[i].Replaced = oldFile.Replaced
+
+		original := ""
+		if oldFile.Replaced {
+			original = is.findBackup(old, oldFile)
+		}
+		if original == "" {
+			if backups != nil {
+				if err := backups.forget(fullPath); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		if backups == nil {
+			backups = newBackupSet(is.backupDir, mod.ID)
+		}
+		if err := backups.adopt(fullPath, original); err != nil {
+			return fmt.Errorf("reprise de la sauvegarde de %s: %w", fullPath, err)
+		}
+	}
+	switch {
+	case backups == nil:
+	case backups.IsEmpty():
+		if manifest.BackupID != "" {
+			is.DeleteBackupSet(manifest.BackupID)
+		}
+		manifest.BackupID = ""
+	default:
+		manifest.BackupID = backups.ID()
+	}
+
+	// Même ID : le manifeste de old vient d'être remplacé, rien d'autre à retirer
+	if old.ModID == manifest.ModID {
+		return manifest.Save(is.manifestDir)
+	}
+	if err := manifest.Save(is.manifestDir); err != nil {
+		return err
+	}
+
+	rest := *old
+	rest.Files = make([]models.InstalledFile, 0, len(old.Files))
+	for _, file := range old.Files {
+		if !remaining[filepath.Clean(file.FullPath())] {
+			rest.Files = append(rest.Files, file)
+		}
+	}
+	report, err := is.uninstallManifest(&rest, true)
+	if err != nil {
+		return err
+	}
+	for _, warning := range report.Warnings {
+		fmt.Printf("Mise à jour de %s: %s\n", old.Name, warning)
+	}
+	return nil
+}
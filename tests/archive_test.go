@@ -0,0 +1,120 @@
+package tests
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"mod-installer/models"
+	"mod-installer/utils"
+)
+
+func TestExtractSevenZip(t *testing.T) {
+	dataPath := t.TempDir()
+	scriptsPath := t.TempDir()
+
+	var calls int
+	written, err := utils.ExtractSevenZip(context.Background(), scriptsPath, dataPath, "testdata/mod.7z",
+		func(currentFile string, processed, total int) {
+			calls++
+			if total != 3 {
+				t.Errorf("expected total 3, got %d", total)
+			}
+		})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(written) != 3 || calls != 3 {
+		t.Fatalf("expected 3 files and 3 callbacks, got %d and %d", len(written), calls)
+	}
+
+	expected := map[string]string{
+		filepath.Join(dataPath, "units.pack"):         "7z pack content",
+		filepath.Join(dataPath, "sub", "extra.pack"):  "extra",
+		filepath.Join(scriptsPath, "user.script.txt"): "7z script",
+	}
+	for path, content := range expected {
+		if data, err := os.ReadFile(path); err != nil || string(data) != content {
+			t.Errorf("%s: got %q (%v), want %q", path, data, err, content)
+		}
+	}
+}
+
+func TestExtractSevenZipRejectsPathTraversal(t *testing.T) {
+	root := t.TempDir()
+	dataPath := filepath.Join(root, "data")
+
+	if _, err := utils.ExtractSevenZip(context.Background(), dataPath, dataPath, "testdata/traversal.7z", nil); err == nil {
+		t.Fatal("expected path traversal to be rejected")
+	}
+	if fileExists(filepath.Join(root, "evil.pack")) {
+		t.Fatal("file written outside of the destination")
+	}
+}
+
+func TestExtractSevenZipHonorsCancellation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	dataPath := t.TempDir()
+	if _, err := utils.ExtractSevenZip(ctx, dataPath, dataPath, "testdata/mod.7z", nil); err != context.Canceled {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestInstallSevenZipMod(t *testing.T) {
+	installer, _ := newTestInstaller(t)
+
+	mod := &models.Mod{ID: "seven", Name: "Seven", Version: "1"}
+	if err := installer.InstallMod(context.Background(), mod, "testdata/mod.7z", nil); err != nil {
+		t.Fatal(err)
+	}
+	if installed, err := installer.GetInstallationStatus(mod); !installed {
+		t.Fatalf("7z mod should be installed: %v", err)
+	}
+}
+
+func TestDetectFormat(t *testing.T) {
+	tarHeader := make([]byte, 512)
+	copy(tarHeader[257:], "ustar\x0000")
+
+	cases := map[string]struct {
+		head []byte
+		want utils.ArchiveFormat
+	}{
+		"zip":   {[]byte("PK\x03\x04rest"), utils.FormatZip},
+		"rar4":  {[]byte("Rar!\x1a\x07\x00rest"), utils.FormatRar},
+		"rar5":  {[]byte("Rar!\x1a\x07\x01\x00rest"), utils.FormatRar},
+		"7z":    {[]byte("7z\xbc\xaf\x27\x1crest"), utils.FormatSevenZip},
+		"pack":  {[]byte("PFH3\x03\x00\x00\x00"), utils.FormatPack},
+		"tar":   {tarHeader, utils.FormatTar},
+		"html":  {[]byte("\n<!DOCTYPE html><html>"), utils.FormatHTML},
+		"other": {[]byte("hello world"), utils.FormatUnknown},
+	}
+	for name, c := range cases {
+		if got := utils.DetectFormat(c.head); got != c.want {
+			t.Errorf("%s: got %q, want %q", name, got, c.want)
+		}
+	}
+
+	if got, err := utils.DetectArchiveFormat("testdata/mod.7z"); err != nil || got != utils.FormatSevenZip {
+		t.Errorf("mod.7z: got %q (%v)", got, err)
+	}
+}
+
+func TestInstallDetectsFormatFromContent(t *testing.T) {
+	installer, cfg := newTestInstaller(t)
+
+	// Une archive ZIP mal nommée en .rar doit tout de même s'installer
+	archive := filepath.Join(cfg.TempPath, "misnamed.rar")
+	writeTestZip(t, archive, map[string]string{"units.pack": "zip content"})
+
+	mod := &models.Mod{ID: "misnamed", Name: "Misnamed", Version: "1"}
+	if err := installer.InstallMod(context.Background(), mod, archive, nil); err != nil {
+		t.Fatal(err)
+	}
+	if !fileExists(filepath.Join(installer.GetDataPath(), "units.pack")) {
+		t.Fatal("units.pack should be installed")
+	}
+}
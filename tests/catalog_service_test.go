@@ -0,0 +1,58 @@
+package tests
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"mod-installer/config"
+	"mod-installer/models"
+	"mod-installer/services"
+)
+
+func newTestCatalogService(t *testing.T, cfg *config.Config, fetch services.CatalogFetcher) *services.CatalogService {
+	t.Helper()
+	cs := services.NewCatalogService(cfg)
+	cs.SetFetcher(fetch)
+	return cs
+}
+
+func TestCatalogCacheServesLastSuccessfulFetch(t *testing.T) {
+	cfg := config.Default()
+	cfg.ConfigPath = filepath.Join(t.TempDir(), "config.json")
+
+	live := map[string]models.Mod{"ntw_fcn_8.2.0": {ID: "ntw_fcn_8.2.0", Name: "FCN", Version: "8.2.0"}}
+	cs := newTestCatalogService(t, cfg, func() (map[string]models.Mod, error) { return live, nil })
+
+	if state := cs.LoadCached(); state.Status != services.CatalogNone || state.Mods != nil {
+		t.Fatalf("expected no cached catalog on first run, got %+v", state)
+	}
+	if state := cs.Refresh(); state.Status != services.CatalogLive || len(state.Mods) != 1 || state.Err != nil {
+		t.Fatalf("expected a live catalog, got %+v", state)
+	}
+
+	// Au démarrage suivant, le catalogue est disponible sans réseau
+	offline := errors.New("network unreachable")
+	cs = newTestCatalogService(t, cfg, func() (map[string]models.Mod, error) { return nil, offline })
+
+	state := cs.LoadCached()
+	if state.Status != services.CatalogCached || state.Mods["ntw_fcn_8.2.0"].Name != "FCN" || state.FetchedAt.IsZero() {
+		t.Fatalf("expected the cached catalog, got %+v", state)
+	}
+
+	state = cs.Refresh()
+	if state.Status != services.CatalogCached || !errors.Is(state.Err, offline) || len(state.Mods) != 1 {
+		t.Fatalf("a failed refresh should keep the cached catalog and report the error, got %+v", state)
+	}
+}
+
+func TestCatalogWithoutCacheOrNetwork(t *testing.T) {
+	cfg := config.Default()
+	cfg.ConfigPath = filepath.Join(t.TempDir(), "config.json")
+
+	cs := newTestCatalogService(t, cfg, func() (map[string]models.Mod, error) { return nil, errors.New("offline") })
+	state := cs.Refresh()
+	if state.Status != services.CatalogNone || state.Err == nil || len(state.Mods) != 0 {
+		t.Fatalf("expected no catalog and an error, got %+v", state)
+	}
+}
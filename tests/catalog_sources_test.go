@@ -0,0 +1,114 @@
+package tests
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"mod-installer/api"
+	"mod-installer/config"
+	"mod-installer/models"
+)
+
+// fakeCatalog est une source de catalogue en mémoire
+type fakeCatalog struct {
+	name string
+	mods map[string]models.Mod
+	err  error
+}
+
+func (f fakeCatalog) Name() string { return f.name }
+func (f fakeCatalog) FetchAll() (map[string]models.Mod, error) {
+	return f.mods, f.err
+}
+
+func TestMultiCatalogPrecedence(t *testing.T) {
+	private := fakeCatalog{name: "private", mods: map[string]models.Mod{
+		"ntw_fcn_8.2.0":  {Name: "FCN (patched)"},
+		"ntw_submod_1.0": {Name: "Submod"},
+	}}
+	down := fakeCatalog{name: "down", err: errors.New("unreachable")}
+	public := fakeCatalog{name: "public", mods: map[string]models.Mod{
+		"ntw_fcn_8.2.0":    {Name: "FCN"},
+		"ntw_darthmod_1.0": {Name: "DarthMod"},
+	}}
+
+	mods, err := api.NewMultiCatalog(private, down, public).FetchAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(mods) != 3 {
+		t.Fatalf("expected 3 merged mods, got %d", len(mods))
+	}
+	if mods["ntw_fcn_8.2.0"].Name != "FCN (patched)" {
+		t.Errorf("the first source should win, got %q", mods["ntw_fcn_8.2.0"].Name)
+	}
+
+	if _, err := api.NewMultiCatalog(down).FetchAll(); err == nil {
+		t.Error("expected an error when every source fails")
+	}
+}
+
+func TestCatalogFromConfig(t *testing.T) {
+	cfg := config.Default()
+	cfg.ModRepositoryURL = "https://api.example.com/mods" // valeur des anciennes configurations
+	catalog, err := api.CatalogFromConfig(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sources := catalog.Sources(); len(sources) != 1 || sources[0].Name() != "mods-meta" {
+		t.Errorf("expected only the public catalog, got %d sources", len(sources))
+	}
+
+	cfg.ModRepositoryURL = "https://catalog.example.org/index.json"
+	cfg.CatalogSources = append(cfg.CatalogSources, config.CatalogSourceConfig{Type: config.CatalogSourceLocal, Path: writeMetaTree(t, testMetaFiles)})
+	catalog, err = api.CatalogFromConfig(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sources := catalog.Sources()
+	if len(sources) != 3 || sources[0].Name() != cfg.ModRepositoryURL {
+		t.Fatalf("expected ModRepositoryURL first then the configured sources, got %d sources", len(sources))
+	}
+	mods, err := sources[2].FetchAll()
+	if err != nil || len(mods) != 2 {
+		t.Errorf("local source should read the directory, got %d mods (%v)", len(mods), err)
+	}
+
+	cfg.CatalogSources = []config.CatalogSourceConfig{{Type: "ftp"}}
+	if _, err := api.CatalogFromConfig(cfg); err == nil {
+		t.Error("expected an error for an unknown source type")
+	}
+}
+
+func TestGitHubCatalogUsesRepoAndBranch(t *testing.T) {
+	index, err := api.BuildCatalogIndex(writeMetaTree(t, testMetaFiles))
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := json.Marshal(index)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Host != "raw.githubusercontent.com" || r.URL.Path != "/team/private-meta/dev/index.json" {
+			t.Errorf("unexpected request %s%s", r.Host, r.URL.Path)
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(data)
+	}))
+	defer server.Close()
+	target, _ := url.Parse(server.URL)
+
+	catalog := api.NewGitHubCatalog("team/private-meta", "dev")
+	catalog.SetTransport(redirectTransport{target: target})
+	mods, err := catalog.FetchAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(mods) != 2 {
+		t.Errorf("expected 2 mods, got %d", len(mods))
+	}
+}
@@ -0,0 +1,155 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"mod-installer/api"
+)
+
+const testIndexURL = "https://raw.githubusercontent.com/awambst/mods-meta/main/index.json"
+
+var testMetaFiles = map[string]string{
+	"ntw/fcn/8.2.0.json":   `{"metadata": {"link": "https://example.com/fcn.zip", "size": "120", "day": "03/12/2023"}, "installation": []}`,
+	"ntw/darthmod/1.json":  `{"schema_version": 2, "name": "DarthMod", "metadata": {"link": "https://example.com/dm.zip", "size": 50, "day": "01/02/2022"}}`,
+	"README.json":          `{"not": "a mod"}`,
+	".git/config.json":     `{}`,
+	"ntw/notes/readme.txt": `notes`,
+}
+
+func writeMetaTree(t *testing.T, files map[string]string) string {
+	t.Helper()
+	root := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+func newTestCatalog(t *testing.T, handler http.HandlerFunc) *api.Catalog {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	target, _ := url.Parse(server.URL)
+	catalog := api.NewCatalog(testIndexURL)
+	catalog.SetTransport(redirectTransport{target: target})
+	return catalog
+}
+
+func TestBuildCatalogIndex(t *testing.T) {
+	index, err := api.BuildCatalogIndex(writeMetaTree(t, testMetaFiles))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(index.Mods) != 2 || index.Mods[0].Path != "ntw/darthmod/1.json" || index.Mods[1].Path != "ntw/fcn/8.2.0.json" {
+		t.Fatalf("unexpected index entries: %+v", index.Mods)
+	}
+	if index.Hash == "" || index.Hash != index.ComputeHash() {
+		t.Errorf("index hash not set")
+	}
+
+	mods, err := index.ToMods()
+	if err != nil {
+		t.Fatal(err)
+	}
+	fcn, ok := mods["ntw_fcn_8.2.0"]
+	if !ok || fcn.ID != "ntw_fcn_8.2.0" || fcn.Name != "FCN" || fcn.Version != "8.2.0" {
+		t.Errorf("path-derived fields not filled: %+v", fcn)
+	}
+	if mods["ntw_darthmod_1"].Name != "DarthMod" {
+		t.Errorf("explicit name should win: %+v", mods["ntw_darthmod_1"])
+	}
+}
+
+func TestBuildCatalogIndexReportsInvalidFile(t *testing.T) {
+	root := writeMetaTree(t, map[string]string{"ntw/broken/1.json": `{"metadata": `})
+	_, err := api.BuildCatalogIndex(root)
+	if err == nil || !strings.Contains(err.Error(), "ntw/broken/1.json") {
+		t.Fatalf("expected an error naming the file, got %v", err)
+	}
+}
+
+func TestCatalogIndexIsRevalidatedWithETag(t *testing.T) {
+	index, err := api.BuildCatalogIndex(writeMetaTree(t, testMetaFiles))
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := json.MarshalIndent(index, "", "  ")
+
+	var full, notModified int
+	catalog := newTestCatalog(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/awambst/mods-meta/main/index.json" {
+			t.Errorf("unexpected request %s", r.URL)
+			http.NotFound(w, r)
+			return
+		}
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		full++
+		w.Header().Set("ETag", `"v1"`)
+		w.Write(data)
+	})
+
+	for i := 0; i < 2; i++ {
+		mods, err := catalog.FetchAll()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(mods) != 2 {
+			t.Fatalf("expected 2 mods, got %d", len(mods))
+		}
+	}
+	if full != 1 || notModified != 1 {
+		t.Errorf("expected one full download then one 304, got %d and %d", full, notModified)
+	}
+}
+
+func TestCatalogFallsBackToTreeWalk(t *testing.T) {
+	for name, indexBody := range map[string]string{
+		"missing index":  "",
+		"tampered index": `{"schema_version": 2, "hash": "bad", "mods": [{"path": "ntw/x/1.json", "meta": {"metadata": {}}}]}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			catalog := newTestCatalog(t, func(w http.ResponseWriter, r *http.Request) {
+				switch {
+				case r.URL.Path == "/awambst/mods-meta/main/index.json" && indexBody != "":
+					w.Write([]byte(indexBody))
+				case r.Host == "api.github.com":
+					w.Write([]byte(`{"tree": [
+						{"path": "ntw", "type": "tree"},
+						{"path": "ntw/fcn/8.2.0.json", "type": "blob"}
+					]}`))
+				case r.URL.Path == "/awambst/mods-meta/main/ntw/fcn/8.2.0.json":
+					w.Write([]byte(testMetaFiles["ntw/fcn/8.2.0.json"]))
+				default:
+					http.NotFound(w, r)
+				}
+			})
+
+			mods, err := catalog.FetchAll()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, ok := mods["ntw_fcn_8.2.0"]; !ok || len(mods) != 1 {
+				t.Errorf("expected the tree walk result, got %+v", mods)
+			}
+		})
+	}
+}
@@ -0,0 +1,194 @@
+package tests
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"mod-installer/models"
+	"mod-installer/services"
+	"mod-installer/utils"
+)
+
+// newArchiveServer sert la même archive ZIP sur toutes les URL
+func newArchiveServer(t *testing.T, archive []byte) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/zip")
+		w.Write(archive)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestListArchiveEntries(t *testing.T) {
+	archive := filepath.Join(t.TempDir(), "mod.zip")
+	writeTestZip(t, archive, map[string]string{"mymod/": "", "mymod/units.pack": "pack", "user.script.txt": "script"})
+
+	entries, err := utils.ListArchiveEntries(archive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sizes := make(map[string]int64)
+	for _, entry := range entries {
+		sizes[entry.Name] = entry.Size
+	}
+	if len(entries) != 2 || sizes["mymod/units.pack"] != 4 || sizes["user.script.txt"] != 6 {
+		t.Errorf("unexpected ZIP entries: %+v", entries)
+	}
+
+	if entries, err := utils.ListArchiveEntries("testdata/mod.7z"); err != nil || len(entries) != 3 {
+		t.Errorf("expected 3 7z entries, got %+v, %v", entries, err)
+	}
+}
+
+func TestCheckFileConflicts(t *testing.T) {
+	installer, cfg := newTestInstaller(t)
+
+	// Mod déjà installé : écrit units.pack
+	installedArchive := filepath.Join(cfg.TempPath, "installed.zip")
+	writeTestZip(t, installedArchive, map[string]string{"units.pack": "installed"})
+	installedMod := &models.Mod{ID: "ntw_installed_1", Name: "Installed", Version: "1", Game: "ntw", Slug: "installed"}
+	if err := installer.InstallMod(context.Background(), installedMod, installedArchive, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// Deux mods prévus : le premier écrit UNITS.pack (même fichier sous Windows) et un
+	// script, le second place le même script via une règle d'installation
+	first := models.Mod{ID: "ntw_first_1", Name: "First", Game: "ntw", Slug: "first"}
+	second := models.Mod{ID: "ntw_second_1", Name: "Second", Game: "ntw", Slug: "second",
+		Installation: []models.InstallRule{{From: "Second/scripts", To: models.InstallTargetScripts}}}
+	// Nouvelle version du mod installé : ses anciens fichiers ne comptent pas
+	upgrade := models.Mod{ID: "ntw_installed_2", Name: "Installed", Game: "ntw", Slug: "installed"}
+
+	archives := map[string]string{
+		first.ID:   filepath.Join(cfg.TempPath, "first.zip"),
+		second.ID:  filepath.Join(cfg.TempPath, "second.zip"),
+		upgrade.ID: filepath.Join(cfg.TempPath, "upgrade.zip"),
+	}
+	writeTestZip(t, archives[first.ID], map[string]string{"UNITS.pack": "first", "user.script.txt": "first", "first.pack": "only"})
+	writeTestZip(t, archives[second.ID], map[string]string{"Second/scripts/user.script.txt": "second", "readme.txt": "ignored"})
+	writeTestZip(t, archives[upgrade.ID], map[string]string{"units.pack": "upgrade", "other.pack": "upgrade"})
+
+	conflicts, err := installer.CheckFileConflicts([]models.Mod{first, second}, archives)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(conflicts) != 2 {
+		t.Fatalf("expected 2 conflicts, got %+v", conflicts)
+	}
+	pack, script := conflicts[0], conflicts[1]
+	if pack.Path != "data/units.pack" || len(pack.Owners) != 2 || !pack.Owners[0].Installed || pack.DefaultWinner() != first.ID {
+		t.Errorf("unexpected pack conflict: %+v", pack)
+	}
+	if script.Path != "scripts/user.script.txt" || script.DefaultWinner() != second.ID {
+		t.Errorf("unexpected script conflict: %+v", script)
+	}
+
+	conflicts, err = installer.CheckFileConflicts([]models.Mod{upgrade}, archives)
+	if err != nil || len(conflicts) != 0 {
+		t.Errorf("the installed version of a planned mod should be ignored, got %+v, %v", conflicts, err)
+	}
+}
+
+func TestFileWinnerKeepsInstalledCopy(t *testing.T) {
+	installer, cfg := newTestInstaller(t)
+	dataPath := installer.GetDataPath()
+
+	installedArchive := filepath.Join(cfg.TempPath, "installed.zip")
+	writeTestZip(t, installedArchive, map[string]string{"units.pack": "installed"})
+	installedMod := &models.Mod{ID: "ntw_installed_1", Name: "Installed", Version: "1"}
+	if err := installer.InstallMod(context.Background(), installedMod, installedArchive, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	archive := filepath.Join(t.TempDir(), "new.zip")
+	writeTestZip(t, archive, map[string]string{"units.pack": "new", "new.pack": incompressible(4096)})
+	content, err := os.ReadFile(archive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := newArchiveServer(t, content)
+	newMod := models.Mod{ID: "ntw_new_1", Name: "New", Version: "1", DownloadURL: server.URL + "/new.zip"}
+
+	// Run réutilise l'archive mise en cache par Download
+	queue := services.NewInstallQueue(services.NewDownloadService(cfg.TempPath, false), installer, 1)
+
+	archives, err := queue.Download(context.Background(), []models.Mod{newMod}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conflicts, err := installer.CheckFileConflicts([]models.Mod{newMod}, archives)
+	if err != nil || len(conflicts) != 1 {
+		t.Fatalf("expected one conflict, got %+v, %v", conflicts, err)
+	}
+
+	queue.SetFileWinners(map[string]string{conflicts[0].Path: installedMod.ID})
+	if err := queue.Run(context.Background(), []models.Mod{newMod}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := os.ReadFile(filepath.Join(dataPath, "units.pack")); string(got) != "installed" {
+		t.Errorf("the installed mod should keep units.pack, got %q", got)
+	}
+	if !fileExists(filepath.Join(dataPath, "new.pack")) {
+		t.Error("non-conflicting files should be installed")
+	}
+	manifest, _ := installer.GetInstallManifest(&newMod)
+	if manifest == nil || len(manifest.Files) != 1 {
+		t.Errorf("the skipped file should not be recorded for the new mod: %+v", manifest)
+	}
+}
+
+func TestModLosingEveryFileDoesNotAbortQueue(t *testing.T) {
+	installer, cfg := newTestInstaller(t)
+	dataPath := installer.GetDataPath()
+
+	installedArchive := filepath.Join(cfg.TempPath, "installed.zip")
+	writeTestZip(t, installedArchive, map[string]string{"units.pack": "installed"})
+	installedMod := &models.Mod{ID: "ntw_installed_1", Name: "Installed", Version: "1"}
+	if err := installer.InstallMod(context.Background(), installedMod, installedArchive, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	loserArchive := filepath.Join(t.TempDir(), "loser.zip")
+	writeTestZip(t, loserArchive, map[string]string{"units.pack": incompressible(4096)})
+	otherArchive := filepath.Join(t.TempDir(), "other.zip")
+	writeTestZip(t, otherArchive, map[string]string{"other.pack": incompressible(4096)})
+	mods := make([]models.Mod, 0, 2)
+	for _, m := range []struct{ id, archive string }{{"ntw_loser_1", loserArchive}, {"ntw_other_1", otherArchive}} {
+		content, err := os.ReadFile(m.archive)
+		if err != nil {
+			t.Fatal(err)
+		}
+		server := newArchiveServer(t, content)
+		mods = append(mods, models.Mod{ID: m.id, Name: m.id, Version: "1", DownloadURL: server.URL + "/" + filepath.Base(m.archive)})
+	}
+
+	queue := services.NewInstallQueue(services.NewDownloadService(cfg.TempPath, false), installer, 1)
+	archives, err := queue.Download(context.Background(), mods, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conflicts, err := installer.CheckFileConflicts(mods, archives)
+	if err != nil || len(conflicts) != 1 {
+		t.Fatalf("expected one conflict, got %+v, %v", conflicts, err)
+	}
+
+	queue.SetFileWinners(map[string]string{conflicts[0].Path: installedMod.ID})
+	if err := queue.Run(context.Background(), mods, nil); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := os.ReadFile(filepath.Join(dataPath, "units.pack")); string(got) != "installed" {
+		t.Errorf("the installed mod should keep units.pack, got %q", got)
+	}
+	if !fileExists(filepath.Join(dataPath, "other.pack")) {
+		t.Error("the next mod in the queue should be installed")
+	}
+	manifest, _ := installer.GetInstallManifest(&mods[0])
+	if manifest == nil || len(manifest.Files) != 0 {
+		t.Errorf("the mod should be recorded with no files: %+v", manifest)
+	}
+}
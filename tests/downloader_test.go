@@ -0,0 +1,150 @@
+package tests
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"mod-installer/models"
+	"mod-installer/services"
+)
+
+// zipBytes retourne une archive ZIP en mémoire assez grande pour passer le contrôle de taille
+func zipBytes(t *testing.T) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	f, err := w.CreateHeader(&zip.FileHeader{Name: "units.pack", Method: zip.Store})
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Write(bytes.Repeat([]byte("x"), 4096))
+	w.Close()
+	return buf.Bytes()
+}
+
+func TestDownloadSniffsFormatAndFileName(t *testing.T) {
+	archive := zipBytes(t)
+	pack := append([]byte("PFH3"), bytes.Repeat([]byte{0}, 4096)...)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/archive":
+			// Annoncé comme RAR mais contient un ZIP
+			w.Header().Set("Content-Disposition", `attachment; filename="mod.rar"`)
+			w.Write(archive)
+		case "/pack":
+			w.Header().Set("Content-Disposition", `attachment; filename*=UTF-8''my%20units.pack`)
+			w.Write(pack)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	ds := services.NewDownloadService(t.TempDir(), false)
+
+	zipMod := &models.Mod{ID: "zip_mod", Version: "1", DownloadURL: server.URL + "/archive"}
+	path, err := ds.DownloadMod(context.Background(), zipMod, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(path, ".zip") {
+		t.Fatalf("expected cached file to be named .zip, got %s", path)
+	}
+	if !ds.IsModCached(zipMod) {
+		t.Fatal("mod should be cached")
+	}
+
+	packMod := &models.Mod{ID: "pack_mod", Version: "1", DownloadURL: server.URL + "/pack"}
+	path, err = ds.DownloadMod(context.Background(), packMod, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if filepath.Base(path) != "my units.pack" {
+		t.Fatalf("raw pack should keep its Content-Disposition name, got %s", path)
+	}
+}
+
+// resumableServer sert content avec ServeContent (Range/If-Range) et coupe la première
+// réponse après cut octets
+func resumableServer(t *testing.T, content []byte, etag string, cut int, ranges *[]string) *httptest.Server {
+	t.Helper()
+	first := true
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*ranges = append(*ranges, r.Header.Get("Range"))
+		if etag != "" {
+			w.Header().Set("ETag", etag)
+		}
+		if first {
+			first = false
+			w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+			w.Write(content[:cut])
+			w.(http.Flusher).Flush()
+			panic(http.ErrAbortHandler)
+		}
+		http.ServeContent(w, r, "mod.zip", time.Time{}, bytes.NewReader(content))
+	}))
+}
+
+func TestDownloadResumesWithRange(t *testing.T) {
+	content := zipBytes(t)
+	var ranges []string
+	server := resumableServer(t, content, `"v1"`, 1000, &ranges)
+	defer server.Close()
+
+	ds := services.NewDownloadService(t.TempDir(), false)
+	mod := &models.Mod{ID: "resume", Version: "1", DownloadURL: server.URL + "/mod.zip"}
+
+	if _, err := ds.DownloadMod(context.Background(), mod, nil); err == nil {
+		t.Fatal("first download should fail when the connection drops")
+	}
+
+	var lastDownloaded, lastTotal int64
+	path, err := ds.DownloadMod(context.Background(), mod, func(downloaded, total int64) {
+		lastDownloaded, lastTotal = downloaded, total
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(ranges) != 2 || ranges[1] != "bytes=1000-" {
+		t.Fatalf("expected second request to resume at 1000, got %q", ranges)
+	}
+	if lastDownloaded != int64(len(content)) || lastTotal != int64(len(content)) {
+		t.Fatalf("progress should include the resumed offset, got %d/%d", lastDownloaded, lastTotal)
+	}
+	if data, _ := os.ReadFile(path); !bytes.Equal(data, content) {
+		t.Fatal("resumed file differs from the original")
+	}
+}
+
+func TestDownloadRestartsWithoutValidator(t *testing.T) {
+	content := zipBytes(t)
+	var ranges []string
+	server := resumableServer(t, content, "", 1000, &ranges)
+	defer server.Close()
+
+	ds := services.NewDownloadService(t.TempDir(), false)
+	mod := &models.Mod{ID: "restart", Version: "1", DownloadURL: server.URL + "/mod.zip"}
+
+	ds.DownloadMod(context.Background(), mod, nil)
+	path, err := ds.DownloadMod(context.Background(), mod, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ranges) != 2 || ranges[1] != "" {
+		t.Fatalf("download without ETag/Last-Modified must restart from zero, got %q", ranges)
+	}
+	if data, _ := os.ReadFile(path); !bytes.Equal(data, content) {
+		t.Fatal("downloaded file differs from the original")
+	}
+}
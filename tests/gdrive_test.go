@@ -0,0 +1,182 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"mod-installer/models"
+	"mod-installer/services"
+)
+
+// redirectTransport envoie toutes les requêtes, quel que soit l'hôte, vers le serveur de test
+type redirectTransport struct {
+	target *url.URL
+}
+
+func (rt redirectTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	clone := req.Clone(req.Context())
+	clone.URL.Scheme = rt.target.Scheme
+	clone.URL.Host = rt.target.Host
+	clone.Host = req.URL.Host
+	resp, err := http.DefaultTransport.RoundTrip(clone)
+	if resp != nil {
+		resp.Request = req
+	}
+	return resp, err
+}
+
+func newDriveDownloader(t *testing.T, handler http.HandlerFunc) *services.DownloadService {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	target, _ := url.Parse(server.URL)
+	ds := services.NewDownloadService(t.TempDir(), false)
+	ds.SetTransport(redirectTransport{target: target})
+	return ds
+}
+
+func serveFixture(t *testing.T, w http.ResponseWriter, name string) {
+	t.Helper()
+	page, err := os.ReadFile("testdata/" + name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write(page)
+}
+
+func TestDriveVirusScanFormIsConfirmed(t *testing.T) {
+	archive := zipBytes(t)
+	ds := newDriveDownloader(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Host == "drive.google.com" && r.URL.Path == "/uc":
+			http.SetCookie(w, &http.Cookie{Name: "NID", Value: "session", Path: "/"})
+			serveFixture(t, w, "drive_virus_scan.html")
+		case r.Host == "drive.usercontent.google.com" && r.URL.Path == "/download":
+			q := r.URL.Query()
+			if q.Get("id") != "1FcnTestFileId_abc" || q.Get("confirm") != "t" || q.Get("uuid") == "" {
+				t.Errorf("unexpected confirmation query: %s", r.URL.RawQuery)
+			}
+			w.Header().Set("Content-Type", "application/x-zip-compressed")
+			w.Write(archive)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	mod := &models.Mod{ID: "drive_form", Version: "1", DownloadURL: "https://drive.google.com/file/d/1FcnTestFileId_abc/view?usp=sharing"}
+	path, err := ds.DownloadMod(context.Background(), mod, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(path, ".zip") {
+		t.Fatalf("expected a cached zip, got %s", path)
+	}
+}
+
+func TestDriveLegacyConfirmLinkKeepsCookies(t *testing.T) {
+	archive := zipBytes(t)
+	ds := newDriveDownloader(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/uc" {
+			http.NotFound(w, r)
+			return
+		}
+		if r.URL.Query().Get("confirm") == "" {
+			http.SetCookie(w, &http.Cookie{Name: "download_warning_1305_1LegacyFileId_xyz", Value: "Xq7T", Path: "/"})
+			serveFixture(t, w, "drive_confirm_link.html")
+			return
+		}
+		if cookie, err := r.Cookie("download_warning_1305_1LegacyFileId_xyz"); err != nil || cookie.Value != "Xq7T" {
+			t.Error("confirmation request must carry the download_warning cookie")
+		}
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.Write(archive)
+	})
+
+	mod := &models.Mod{ID: "drive_link", Version: "1", DownloadURL: "https://drive.google.com/uc?id=1LegacyFileId_xyz"}
+	if _, err := ds.DownloadMod(context.Background(), mod, nil); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDriveQuotaPageIsReported(t *testing.T) {
+	ds := newDriveDownloader(t, func(w http.ResponseWriter, r *http.Request) {
+		serveFixture(t, w, "drive_quota.html")
+	})
+
+	mod := &models.Mod{ID: "drive_quota", Version: "1", DownloadURL: "https://drive.google.com/file/d/1QuotaFile/view"}
+	_, err := ds.DownloadMod(context.Background(), mod, nil)
+	if err == nil || !strings.Contains(err.Error(), "quota") {
+		t.Fatalf("expected a quota error, got %v", err)
+	}
+}
+
+func TestDriveFolderIsAssembledAndInstalled(t *testing.T) {
+	pack := append([]byte("PFH4"), bytes.Repeat([]byte{0}, 2048)...)
+	ds := newDriveDownloader(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/embeddedfolderview" && r.URL.Query().Get("id") == "1RootFolderId":
+			serveFixture(t, w, "drive_folder.html")
+		case r.URL.Path == "/embeddedfolderview" && r.URL.Query().Get("id") == "1ExtrasFolderId":
+			serveFixture(t, w, "drive_subfolder.html")
+		case r.URL.Path == "/uc":
+			w.Header().Set("Content-Type", "application/octet-stream")
+			switch r.URL.Query().Get("id") {
+			case "1UnitsZipFileId":
+				w.Write(zipBytes(t))
+			case "1ScriptFileId":
+				w.Write([]byte("enable_mod units\n"))
+			case "1ExtraPackFileId":
+				w.Write(pack)
+			default:
+				http.NotFound(w, r)
+			}
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	mod := &models.Mod{ID: "drive_folder", Name: "Drive folder", Version: "1", DownloadURL: "https://drive.google.com/drive/folders/1RootFolderId?usp=sharing"}
+	tree, err := ds.DownloadMod(context.Background(), mod, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ds.IsModCached(mod) || ds.GetCachedModPath(mod) != tree {
+		t.Fatalf("assembled folder should be cached at %s", tree)
+	}
+
+	installer, _ := newTestInstaller(t)
+	if err := installer.InstallMod(context.Background(), mod, tree, nil); err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range []string{
+		filepath.Join(installer.GetDataPath(), "units.pack"),
+		filepath.Join(installer.GetDataPath(), "extras", "extra_&_more.pack"),
+		filepath.Join(installer.GetScriptsPath(), "user.script.txt"),
+	} {
+		if !fileExists(path) {
+			t.Errorf("expected %s to be installed", path)
+		}
+	}
+}
+
+func TestDriveFolderUnsharedIsReported(t *testing.T) {
+	ds := newDriveDownloader(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte("<!DOCTYPE html><html><body>Sign in</body></html>"))
+	})
+
+	mod := &models.Mod{ID: "drive_private", Version: "1", DownloadURL: "https://drive.google.com/drive/folders/1PrivateFolderId"}
+	_, err := ds.DownloadMod(context.Background(), mod, nil)
+	if err == nil || !strings.Contains(err.Error(), "non partagé") {
+		t.Fatalf("expected an unshared folder error, got %v", err)
+	}
+}
@@ -0,0 +1,326 @@
+package tests
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"mod-installer/config"
+	"mod-installer/models"
+	"mod-installer/services"
+)
+
+// newTestInstaller prépare une arborescence de jeu factice et un InstallerService
+func newTestInstaller(t *testing.T) (*services.InstallerService, *config.Config) {
+	t.Helper()
+	root := t.TempDir()
+
+	cfg := config.Default()
+	cfg.GamePath = filepath.Join(root, "Napoleon Total War")
+	cfg.ScriptsPath = filepath.Join(root, "Napoleon")
+	cfg.TempPath = filepath.Join(root, "cache", "temp")
+	cfg.ModsPath = filepath.Join(root, "cache", "mods")
+	cfg.ConfigPath = filepath.Join(root, "cache", "config.json")
+
+	for _, dir := range []string{filepath.Join(cfg.GamePath, "data"), filepath.Join(cfg.ScriptsPath, "scripts"), cfg.TempPath} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return services.NewInstallerService(cfg), cfg
+}
+
+// writeTestZip crée une archive ZIP contenant les fichiers donnés
+func writeTestZip(t *testing.T, path string, files map[string]string) {
+	t.Helper()
+	out, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer out.Close()
+
+	w := zip.NewWriter(out)
+	for name, content := range files {
+		f, err := w.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := f.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestInstallAndUninstallMod(t *testing.T) {
+	installer, cfg := newTestInstaller(t)
+	dataPath := installer.GetDataPath()
+
+	archive := filepath.Join(cfg.TempPath, "mod.zip")
+	writeTestZip(t, archive, map[string]string{
+		"mymod/units.pack": "pack content",
+		"user.script.txt":  "script content",
+	})
+
+	mod := &models.Mod{ID: "ntw_test_1.0", Name: "Test", Version: "1.0"}
+	if err := installer.InstallMod(context.Background(), mod, archive, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if installed, err := installer.GetInstallationStatus(mod); !installed || err != nil {
+		t.Fatalf("mod should be installed, got %v, %v", installed, err)
+	}
+	if !mod.IsInstalled(filepath.Join(cfg.CacheDir(), "installed"), cfg.GamePath) {
+		t.Fatal("Mod.IsInstalled should agree with the manifest")
+	}
+
+	// Une modification externe doit être détectée
+	packPath := filepath.Join(dataPath, "mymod", "units.pack")
+	if err := os.WriteFile(packPath, []byte("pack CONTENT"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if check, _ := installer.CheckInstallation(mod); check.State != models.InstallStateModified {
+		t.Fatalf("expected modified state, got %s", check.State)
+	}
+
+	report, err := installer.UninstallMod(mod, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Removed) != 2 {
+		t.Fatalf("expected 2 removed files, got %v", report.Removed)
+	}
+	if _, err := os.Stat(filepath.Join(dataPath, "mymod")); !os.IsNotExist(err) {
+		t.Fatal("empty mod directory should have been pruned")
+	}
+	if installed, _ := installer.GetInstallationStatus(mod); installed {
+		t.Fatal("mod should no longer be installed")
+	}
+}
+
+func TestUninstallRefusesSharedFiles(t *testing.T) {
+	installer, cfg := newTestInstaller(t)
+
+	first := filepath.Join(cfg.TempPath, "first.zip")
+	second := filepath.Join(cfg.TempPath, "second.zip")
+	writeTestZip(t, first, map[string]string{"shared.pack": "a", "first.pack": "a"})
+	writeTestZip(t, second, map[string]string{"shared.pack": "b"})
+
+	modA := &models.Mod{ID: "a", Name: "A", Version: "1"}
+	modB := &models.Mod{ID: "b", Name: "B", Version: "1"}
+	for mod, archive := range map[*models.Mod]string{modA: first, modB: second} {
+		if err := installer.InstallMod(context.Background(), mod, archive, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := installer.UninstallMod(modA, false); err == nil {
+		t.Fatal("uninstall should be refused while B owns shared.pack")
+	}
+
+	report, err := installer.UninstallMod(modA, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Kept) != 1 || !fileExists(filepath.Join(installer.GetDataPath(), "shared.pack")) {
+		t.Fatalf("shared.pack should be kept, report: %+v", report)
+	}
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+func TestBackupRestoresOverwrittenFiles(t *testing.T) {
+	installer, cfg := newTestInstaller(t)
+	original := filepath.Join(installer.GetDataPath(), "units.pack")
+	if err := os.WriteFile(original, []byte("original"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	archive := filepath.Join(cfg.TempPath, "mod.zip")
+	writeTestZip(t, archive, map[string]string{"units.pack": "modded"})
+
+	mod := &models.Mod{ID: "backup_test", Name: "Backup", Version: "1"}
+	if err := installer.InstallMod(context.Background(), mod, archive, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	sets, err := installer.ListBackupSets()
+	if err != nil || len(sets) != 1 || len(sets[0].Entries) != 1 {
+		t.Fatalf("expected one backup set with one entry, got %+v (%v)", sets, err)
+	}
+
+	report, err := installer.UninstallMod(mod, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Restored) != 1 {
+		t.Fatalf("expected units.pack to be restored, report: %+v", report)
+	}
+	if content, _ := os.ReadFile(original); string(content) != "original" {
+		t.Fatalf("unexpected content after uninstall: %q", content)
+	}
+}
+
+func TestRestoreBackupSetUpdatesManifest(t *testing.T) {
+	installer, cfg := newTestInstaller(t)
+	dataPath := installer.GetDataPath()
+	for name, content := range map[string]string{"units.pack": "original units", "sounds.pack": "original sounds"} {
+		if err := os.WriteFile(filepath.Join(dataPath, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	partialArchive := filepath.Join(cfg.TempPath, "partial.zip")
+	writeTestZip(t, partialArchive, map[string]string{"units.pack": "modded", "extra.pack": "extra"})
+	partial := &models.Mod{ID: "restore_partial", Name: "Partial", Version: "1"}
+	replacedArchive := filepath.Join(cfg.TempPath, "replaced.zip")
+	writeTestZip(t, replacedArchive, map[string]string{"sounds.pack": "modded"})
+	replaced := &models.Mod{ID: "restore_replaced", Name: "Replaced", Version: "1"}
+	for _, install := range []struct {
+		mod     *models.Mod
+		archive string
+	}{{partial, partialArchive}, {replaced, replacedArchive}} {
+		if err := installer.InstallMod(context.Background(), install.mod, install.archive, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	sets, err := installer.ListBackupSets()
+	if err != nil || len(sets) != 2 {
+		t.Fatalf("expected two backup sets, got %+v (%v)", sets, err)
+	}
+	for _, set := range sets {
+		if _, err := installer.RestoreBackupSet(set.ID); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if content, _ := os.ReadFile(filepath.Join(dataPath, "units.pack")); string(content) != "original units" {
+		t.Fatalf("units.pack not restored: %q", content)
+	}
+	manifest, _ := installer.GetInstallManifest(partial)
+	if manifest == nil || len(manifest.Files) != 1 || manifest.Files[0].Path != "extra.pack" {
+		t.Fatalf("only extra.pack should remain in the manifest: %+v", manifest)
+	}
+	if manifest, _ := installer.GetInstallManifest(replaced); manifest != nil {
+		t.Fatalf("a mod with every file restored should no longer be installed: %+v", manifest)
+	}
+}
+
+func TestInstallRollsBackOnCommitFailure(t *testing.T) {
+	installer, cfg := newTestInstaller(t)
+	dataPath := installer.GetDataPath()
+
+	// data/blocker est un fichier : impossible d'y créer blocker/units.pack
+	if err := os.WriteFile(filepath.Join(dataPath, "blocker"), []byte("keep"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	archive := filepath.Join(cfg.TempPath, "broken.zip")
+	out, err := os.Create(archive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := zip.NewWriter(out)
+	for _, name := range []string{"first.pack", "blocker/units.pack"} {
+		f, _ := w.Create(name)
+		f.Write([]byte(name))
+	}
+	w.Close()
+	out.Close()
+
+	// L'extraction en staging réussit, le commit échoue sur le second fichier
+	mod := &models.Mod{ID: "broken", Name: "Broken", Version: "1"}
+	if err := installer.InstallMod(context.Background(), mod, archive, nil); err == nil {
+		t.Fatal("install should fail")
+	}
+
+	if fileExists(filepath.Join(dataPath, "first.pack")) {
+		t.Fatal("first.pack should have been rolled back")
+	}
+	if content, _ := os.ReadFile(filepath.Join(dataPath, "blocker")); string(content) != "keep" {
+		t.Fatal("pre-existing file should be untouched")
+	}
+	if manifest, _ := installer.GetInstallManifest(mod); manifest != nil {
+		t.Fatal("no manifest should be recorded for a failed install")
+	}
+	if stages, _ := filepath.Glob(filepath.Join(cfg.TempPath, "install_*")); len(stages) != 0 {
+		t.Fatalf("staging directories left behind: %v", stages)
+	}
+}
+
+func TestInstallCancelledLeavesGameUntouched(t *testing.T) {
+	installer, cfg := newTestInstaller(t)
+
+	archive := filepath.Join(cfg.TempPath, "mod.zip")
+	writeTestZip(t, archive, map[string]string{"units.pack": "modded"})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	mod := &models.Mod{ID: "cancelled", Name: "Cancelled", Version: "1"}
+	if err := installer.InstallMod(ctx, mod, archive, nil); err == nil {
+		t.Fatal("install should fail when the context is cancelled")
+	}
+	if fileExists(filepath.Join(installer.GetDataPath(), "units.pack")) {
+		t.Fatal("cancelled install must not write into data/")
+	}
+}
+
+func TestRecoverRollsBackWhenStagingIsLost(t *testing.T) {
+	installer, cfg := newTestInstaller(t)
+	original := filepath.Join(installer.GetDataPath(), "units.pack")
+	if err := os.WriteFile(original, []byte("original"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Commit interrompu avant toute opération, zone de staging supprimée depuis
+	journalDir := filepath.Join(cfg.CacheDir(), "journal")
+	if err := os.MkdirAll(journalDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	stageDir := filepath.Join(cfg.TempPath, "install_lost")
+	header := map[string]interface{}{
+		"mod_id":    "interrupted",
+		"name":      "Interrupted",
+		"version":   "1",
+		"stage_dir": stageDir,
+		"backup_id": "interrupted_1",
+		"ops": []map[string]interface{}{{
+			"src":     filepath.Join(stageDir, "data", "units.pack"),
+			"dest":    original,
+			"root":    installer.GetDataPath(),
+			"name":    "units.pack",
+			"existed": true,
+		}},
+	}
+	data, err := json.Marshal(header)
+	if err != nil {
+		t.Fatal(err)
+	}
+	journal := filepath.Join(journalDir, "interrupted.jsonl")
+	if err := os.WriteFile(journal, append(data, '\n'), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := installer.RecoverInterruptedInstalls(); err != nil {
+		t.Fatal(err)
+	}
+	if content, _ := os.ReadFile(original); string(content) != "original" {
+		t.Fatalf("game file should be untouched, got %q", content)
+	}
+	if fileExists(journal) {
+		t.Fatal("journal should be removed after recovery")
+	}
+	if manifest, _ := installer.GetInstallManifest(&models.Mod{ID: "interrupted"}); manifest != nil {
+		t.Fatal("no manifest should be recorded for a rolled back install")
+	}
+}
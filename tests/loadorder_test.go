@@ -0,0 +1,75 @@
+package tests
+
+import (
+	"path/filepath"
+	"testing"
+
+	"mod-installer/api"
+	"mod-installer/config"
+	"mod-installer/models"
+	"mod-installer/services"
+)
+
+func orderedKeys(mods []models.Mod) []string {
+	keys := make([]string, len(mods))
+	for i, mod := range mods {
+		keys[i] = mod.GroupKey()
+	}
+	return keys
+}
+
+func loadOrderMods() []models.Mod {
+	return []models.Mod{
+		{ID: "ntw_ui_1", Game: "ntw", Slug: "ui", Priority: 10},
+		{ID: "ntw_fcn_8", Game: "ntw", Slug: "fcn"},
+		{ID: "ntw_base_1", Game: "ntw", Slug: "base", Priority: -5},
+		{ID: "ntw_ai_2", Game: "ntw", Slug: "ai"},
+	}
+}
+
+func TestLoadOrderFollowsCatalogPriority(t *testing.T) {
+	cfg := config.Default()
+	cfg.ConfigPath = filepath.Join(t.TempDir(), "config.json")
+
+	mods := loadOrderMods()
+	services.NewLoadOrderService(cfg).SortMods(mods)
+	if got := orderedKeys(mods); got[0] != "ntw_base" || got[1] != "ntw_ai" || got[2] != "ntw_fcn" || got[3] != "ntw_ui" {
+		t.Errorf("unexpected default order: %v", got)
+	}
+
+	mod, err := api.ParseModMeta([]byte(`{"schema_version": 2, "metadata": {"link": "https://mods.org/a.zip", "size": 1, "day": "01/01/2024"}, "priority": 7}`))
+	if err != nil || mod.Priority != 7 {
+		t.Errorf("expected the catalog priority to be parsed, got %d, %v", mod.Priority, err)
+	}
+}
+
+func TestLoadOrderOverridesArePersisted(t *testing.T) {
+	cfg := config.Default()
+	cfg.ConfigPath = filepath.Join(t.TempDir(), "config.json")
+
+	mods := loadOrderMods()
+	lo := services.NewLoadOrderService(cfg)
+	lo.SortMods(mods)
+
+	// ui, installé en dernier par défaut, passe avant fcn
+	if err := lo.Move(orderedKeys(mods), "ntw_ui", -1); err != nil {
+		t.Fatal(err)
+	}
+
+	// Un nouveau mod sans priorité se place selon le catalogue parmi les mods ordonnés
+	mods = append(loadOrderMods(), models.Mod{ID: "ntw_late_1", Game: "ntw", Slug: "late", Priority: 20})
+	services.NewLoadOrderService(cfg).SortMods(mods)
+	got := orderedKeys(mods)
+	want := []string{"ntw_base", "ntw_ai", "ntw_ui", "ntw_fcn", "ntw_late"}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("expected %v after reload, got %v", want, got)
+		}
+	}
+
+	groups := models.GroupMods(map[string]models.Mod{"a": mods[3], "b": mods[2]}).Groups()
+	services.NewLoadOrderService(cfg).SortGroups(groups)
+	if groups[0].Key() != "ntw_ui" {
+		t.Errorf("groups should follow the saved order, got %v", groups)
+	}
+}
@@ -0,0 +1,65 @@
+package tests
+
+import (
+	"path/filepath"
+	"testing"
+
+	"mod-installer/api"
+	"mod-installer/config"
+)
+
+func TestLocalCatalogDerivesFieldsFromPath(t *testing.T) {
+	files := map[string]string{
+		"ntw/fcn/8.2.0.json":     testMetaFiles["ntw/fcn/8.2.0.json"],
+		"ntw/darthmod/1.json":    testMetaFiles["ntw/darthmod/1.json"],
+		"ntw/broken/2.json":      `{"metadata": `,
+		"index.json":             `{"mods": []}`,
+		".github/workflows.json": `{}`,
+	}
+	catalog := api.NewLocalCatalog(writeMetaTree(t, files))
+
+	mods, err := catalog.FetchAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(mods) != 2 {
+		t.Fatalf("expected 2 mods, got %d", len(mods))
+	}
+
+	fcn := mods["ntw_fcn_8.2.0"]
+	if fcn.ID != "ntw_fcn_8.2.0" || fcn.Name != "FCN" || fcn.Version != "8.2.0" || fcn.Description != "Mod FCN pour NTW" {
+		t.Errorf("unexpected derived fields: %+v", fcn)
+	}
+	if dm := mods["ntw_darthmod_1"]; dm.Name != "DarthMod" || dm.Version != "1" {
+		t.Errorf("unexpected derived fields: %+v", dm)
+	}
+
+	loadErrors := catalog.LoadErrors()
+	if len(loadErrors) != 1 || loadErrors[0].Path != "ntw/broken/2.json" {
+		t.Errorf("expected the broken file to be reported, got %v", loadErrors)
+	}
+}
+
+func TestLocalCatalogMissingDirectory(t *testing.T) {
+	if _, err := api.NewLocalCatalog(filepath.Join(t.TempDir(), "missing")).FetchAll(); err == nil {
+		t.Fatal("expected an error for a missing directory")
+	}
+}
+
+func TestLocalCatalogPathReplacesConfiguredSources(t *testing.T) {
+	cfg := config.Default()
+	cfg.ModRepositoryURL = "https://catalog.example.org/index.json"
+	cfg.LocalCatalogPath = writeMetaTree(t, testMetaFiles)
+
+	catalog, err := api.CatalogFromConfig(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sources := catalog.Sources(); len(sources) != 1 || sources[0].Name() != cfg.LocalCatalogPath {
+		t.Fatalf("expected only the local catalog, got %d sources", len(sources))
+	}
+	mods, err := catalog.FetchAll()
+	if err != nil || len(mods) != 2 {
+		t.Fatalf("expected 2 mods offline, got %d (%v)", len(mods), err)
+	}
+}
@@ -0,0 +1,58 @@
+package tests
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"mod-installer/models"
+	"mod-installer/services"
+	"mod-installer/utils"
+)
+
+func TestInstallRecordsManifest(t *testing.T) {
+	installer, cfg := newTestInstaller(t)
+
+	archive := filepath.Join(cfg.TempPath, "mod.zip")
+	writeTestZip(t, archive, map[string]string{
+		"mymod/units.pack": "pack content",
+		"user.script.txt":  "script content",
+	})
+	mod := &models.Mod{ID: "ntw_manifest_1.0", Name: "Manifest", Version: "1.0"}
+	if err := installer.InstallMod(context.Background(), mod, archive, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	manifest, err := installer.GetInstallManifest(mod)
+	if err != nil || manifest == nil {
+		t.Fatalf("expected a manifest, got %v", err)
+	}
+	if manifest.ModID != mod.ID || manifest.Version != "1.0" || manifest.GamePath != cfg.GamePath || len(manifest.Files) != 2 {
+		t.Fatalf("unexpected manifest: %+v", manifest)
+	}
+	for _, file := range manifest.Files {
+		sum, err := utils.CalculateSHA256(file.FullPath())
+		if err != nil || sum != file.SHA256 {
+			t.Errorf("%s: recorded hash does not match the installed file", file.Path)
+		}
+	}
+
+	// Le statut est lu depuis le manifeste
+	if installed, err := installer.GetInstallationStatus(mod); !installed || err != nil {
+		t.Fatalf("mod should be installed, got %v, %v", installed, err)
+	}
+	if err := os.Remove(filepath.Join(installer.GetScriptsPath(), "user.script.txt")); err != nil {
+		t.Fatal(err)
+	}
+	if installed, err := installer.GetInstallationStatus(mod); installed || err == nil {
+		t.Errorf("a partial installation should be reported, got %v, %v", installed, err)
+	}
+
+	// Un autre dossier de jeu ne voit pas l'installation
+	other := *cfg
+	other.GamePath = filepath.Join(t.TempDir(), "Other")
+	if installed, err := services.NewInstallerService(&other).GetInstallationStatus(mod); installed || err != nil {
+		t.Errorf("mod should not be installed in another game folder, got %v, %v", installed, err)
+	}
+}
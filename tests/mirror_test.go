@@ -0,0 +1,146 @@
+package tests
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"testing"
+
+	"mod-installer/models"
+	"mod-installer/services"
+)
+
+// mirrorServer sert l'archive sur /good et échoue sur /down, en comptant les requêtes
+type mirrorServer struct {
+	*httptest.Server
+	mu   sync.Mutex
+	hits map[string]int
+}
+
+func newMirrorServer(t *testing.T, archive []byte) *mirrorServer {
+	t.Helper()
+	ms := &mirrorServer{hits: make(map[string]int)}
+	ms.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ms.mu.Lock()
+		ms.hits[r.URL.Path]++
+		ms.mu.Unlock()
+
+		switch r.URL.Path {
+		case "/good.zip", "/other.zip":
+			w.Header().Set("Content-Type", "application/zip")
+			w.Write(archive)
+		default:
+			http.Error(w, "mirror down", http.StatusServiceUnavailable)
+		}
+	}))
+	t.Cleanup(ms.Close)
+	return ms
+}
+
+func (ms *mirrorServer) count(path string) int {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	return ms.hits[path]
+}
+
+func TestDownloadFailsOverToNextMirror(t *testing.T) {
+	server := newMirrorServer(t, zipBytes(t))
+	ds := services.NewDownloadService(t.TempDir(), false)
+
+	mod := &models.Mod{
+		ID:          "mirrored",
+		Version:     "1",
+		DownloadURL: server.URL + "/down.zip",
+		Mirrors:     []models.Mirror{{URL: server.URL + "/good.zip"}},
+	}
+	if _, err := ds.DownloadMod(context.Background(), mod, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := ds.SuccessfulMirror(mod); got != server.URL+"/good.zip" {
+		t.Errorf("expected the second mirror to be recorded, got %q", got)
+	}
+	if failures := ds.MirrorHealth(server.URL + "/down.zip").Failures; failures != 1 {
+		t.Errorf("expected one recorded failure, got %d", failures)
+	}
+	if !ds.IsModCached(mod) {
+		t.Error("mod should be cached after a mirror succeeded")
+	}
+}
+
+func TestMirrorChecksumOverridesModChecksum(t *testing.T) {
+	archive := zipBytes(t)
+	server := newMirrorServer(t, archive)
+	ds := services.NewDownloadService(t.TempDir(), true)
+
+	mod := &models.Mod{
+		ID:          "checked",
+		Version:     "1",
+		DownloadURL: server.URL + "/good.zip",
+		Checksum:    "0000",
+		Mirrors: []models.Mirror{
+			{URL: server.URL + "/good.zip", Checksum: "1111"},
+			{URL: server.URL + "/other.zip", Checksum: fmt.Sprintf("%x", sha256.Sum256(archive))},
+		},
+	}
+	path, err := ds.DownloadMod(context.Background(), mod, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ds.SuccessfulMirror(mod) != server.URL+"/other.zip" {
+		t.Errorf("expected the mirror with a matching checksum to win, got %q", ds.SuccessfulMirror(mod))
+	}
+
+	// Le fichier en cache est validé avec le checksum du miroir qui l'a fourni
+	if !ds.IsModCached(mod) {
+		t.Error("cached file should be validated against its mirror checksum")
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestRepeatedlyFailingMirrorIsDemoted(t *testing.T) {
+	server := newMirrorServer(t, zipBytes(t))
+	tempDir := t.TempDir()
+
+	for i := 0; i < 4; i++ {
+		ds := services.NewDownloadService(tempDir, false)
+		mod := &models.Mod{
+			ID:          fmt.Sprintf("demote_%d", i),
+			Version:     "1",
+			DownloadURL: server.URL + "/down.zip",
+			Mirrors:     []models.Mirror{{URL: server.URL + "/good.zip"}},
+		}
+		if _, err := ds.DownloadMod(context.Background(), mod, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Après trois échecs consécutifs, le premier miroir n'est plus essayé en premier
+	if hits := server.count("/down.zip"); hits != 3 {
+		t.Errorf("expected the failing mirror to be skipped once demoted, got %d requests", hits)
+	}
+}
+
+func TestAllMirrorsFailing(t *testing.T) {
+	server := newMirrorServer(t, nil)
+	ds := services.NewDownloadService(t.TempDir(), false)
+
+	mod := &models.Mod{
+		ID:          "dead",
+		Version:     "1",
+		DownloadURL: server.URL + "/down.zip",
+		Mirrors:     []models.Mirror{{URL: server.URL + "/gone.zip"}},
+	}
+	if _, err := ds.DownloadMod(context.Background(), mod, nil); err == nil {
+		t.Fatal("expected an error when every mirror fails")
+	}
+	if server.count("/down.zip") != 1 || server.count("/gone.zip") != 1 {
+		t.Errorf("expected each mirror to be tried once, got %v", server.hits)
+	}
+}
@@ -0,0 +1,165 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"mod-installer/utils/ntw"
+)
+
+type testPackFile struct {
+	Path string
+	Data string
+}
+
+// buildTestPack écrit un pack minimal : en-tête, dépendances, index puis données.
+// flags est ajouté au type (0x40 : index daté).
+func buildTestPack(version string, packType ntw.PackType, flags uint32, deps []string, files []testPackFile) []byte {
+	var packIndex, fileIndex, data bytes.Buffer
+	for _, dep := range deps {
+		packIndex.WriteString(dep)
+		packIndex.WriteByte(0)
+	}
+	stamp := time.Date(2013, 9, 3, 12, 0, 0, 0, time.UTC)
+	for _, file := range files {
+		binary.Write(&fileIndex, binary.LittleEndian, uint32(len(file.Data)))
+		if flags&0x40 != 0 {
+			if version == "PFH4" {
+				binary.Write(&fileIndex, binary.LittleEndian, uint32(stamp.Unix()))
+			} else {
+				binary.Write(&fileIndex, binary.LittleEndian, uint64(stamp.UnixNano()/100+116444736000000000))
+			}
+		}
+		fileIndex.WriteString(strings.ReplaceAll(file.Path, "/", "\\"))
+		fileIndex.WriteByte(0)
+		data.WriteString(file.Data)
+	}
+
+	var pack bytes.Buffer
+	pack.WriteString(version)
+	binary.Write(&pack, binary.LittleEndian, []uint32{
+		uint32(packType) | flags,
+		uint32(len(deps)), uint32(packIndex.Len()),
+		uint32(len(files)), uint32(fileIndex.Len()),
+	})
+	switch version {
+	case "PFH2", "PFH3":
+		binary.Write(&pack, binary.LittleEndian, uint64(stamp.UnixNano()/100+116444736000000000))
+	case "PFH4":
+		binary.Write(&pack, binary.LittleEndian, uint32(stamp.Unix()))
+	}
+	pack.Write(packIndex.Bytes())
+	pack.Write(fileIndex.Bytes())
+	pack.Write(data.Bytes())
+	return pack.Bytes()
+}
+
+func TestReadPackPFH3(t *testing.T) {
+	raw := buildTestPack("PFH3", ntw.PackMod, 0, []string{"data.pack", "patch.pack"}, []testPackFile{
+		{"db/units_tables/my_units", "units"},
+		{"text/db/my_units.loc", "loc"},
+	})
+	path := filepath.Join(t.TempDir(), "my_mod.pack")
+	if err := os.WriteFile(path, raw, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	pack, err := ntw.ReadPackFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pack.Version != "PFH3" || pack.Type != ntw.PackMod || pack.Type.String() != "mod" {
+		t.Errorf("unexpected header: %s %s", pack.Version, pack.Type)
+	}
+	if !pack.Timestamp.Equal(time.Date(2013, 9, 3, 12, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected timestamp: %v", pack.Timestamp)
+	}
+	if strings.Join(pack.Dependencies, ",") != "data.pack,patch.pack" {
+		t.Errorf("unexpected dependencies: %v", pack.Dependencies)
+	}
+	if len(pack.Files) != 2 || pack.Files[0].Path != "db/units_tables/my_units" || pack.DataSize() != 8 {
+		t.Fatalf("unexpected index: %+v", pack.Files)
+	}
+
+	// Les offsets désignent les données de chaque fichier
+	loc := pack.Find(`TEXT\db\my_units.loc`)
+	if loc == nil {
+		t.Fatal("loc file not found")
+	}
+	if got := string(raw[loc.Offset : loc.Offset+loc.Size]); got != "loc" {
+		t.Errorf("offset points to %q", got)
+	}
+}
+
+func TestReadPackPFH4Timestamps(t *testing.T) {
+	raw := buildTestPack("PFH4", ntw.PackPatch, 0x40, nil, []testPackFile{
+		{"db/land_units_tables/patch", "land"},
+		{"ui/skins/default/icon.png", "png"},
+	})
+
+	pack, err := ntw.ReadPack(bytes.NewReader(raw))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pack.Type != ntw.PackPatch || pack.Flags != 0x40 || len(pack.Dependencies) != 0 {
+		t.Errorf("unexpected header: %+v", pack)
+	}
+	last := pack.Files[1]
+	if last.Path != "ui/skins/default/icon.png" || last.Timestamp.Year() != 2013 {
+		t.Errorf("unexpected entry: %+v", last)
+	}
+	if got := string(raw[last.Offset : last.Offset+last.Size]); got != "png" {
+		t.Errorf("offset points to %q", got)
+	}
+}
+
+// testdata/ntw_mod.pack reprend l'en-tête écrit par Napoleon : PFH0, sans date ni
+// dépendance, chemins séparés par "\"
+func TestReadPackPFH0(t *testing.T) {
+	raw, err := os.ReadFile("testdata/ntw_mod.pack")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pack, err := ntw.ReadPackFile("testdata/ntw_mod.pack")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pack.Version != "PFH0" || pack.Type != ntw.PackMod || pack.Flags != 0 || !pack.Timestamp.IsZero() || len(pack.Dependencies) != 0 {
+		t.Errorf("unexpected header: %+v", pack)
+	}
+	if len(pack.Files) != 2 {
+		t.Fatalf("expected 2 entries, got %+v", pack.Files)
+	}
+	units := pack.Find("DB\\units_tables\\ntw_mod_units")
+	if units == nil || units.Offset != 82 || units.Size != 11 {
+		t.Fatalf("unexpected entry: %+v", units)
+	}
+	if got := string(raw[units.Offset : units.Offset+units.Size]); got != "units-table" {
+		t.Errorf("offset points to %q", got)
+	}
+	if pack.Files[1].Path != "text/db/ntw_mod.loc" || pack.DataSize() != 14 {
+		t.Errorf("unexpected index: %+v", pack.Files)
+	}
+}
+
+func TestReadPackRejectsInvalid(t *testing.T) {
+	valid := buildTestPack("PFH3", ntw.PackMod, 0, nil, []testPackFile{{"db/a", "a"}})
+
+	cases := map[string][]byte{
+		"magic":     append([]byte("PFH5"), valid[4:]...),
+		"encrypted": buildTestPack("PFH4", ntw.PackMod, 0x80, nil, nil),
+		"truncated": valid[:len(valid)-6],
+		"count":     append(append([]byte{}, valid[:16]...), append([]byte{2, 0, 0, 0}, valid[20:]...)...),
+	}
+	for name, raw := range cases {
+		if _, err := ntw.ReadPack(bytes.NewReader(raw)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package tests
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"mod-installer/models"
+	"mod-installer/utils/ntw"
+)
+
+func TestCheckPackConflicts(t *testing.T) {
+	installer, cfg := newTestInstaller(t)
+
+	// Pack du jeu
+	vanilla := buildTestPack("PFH3", ntw.PackRelease, 0, nil, []testPackFile{
+		{"db/units_tables/units", "vanilla units"},
+		{"ui/skins/default/banner.tga", "banner"},
+	})
+	if err := os.WriteFile(filepath.Join(installer.GetDataPath(), "data.pack"), vanilla, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Mod installé : ajoute un fichier à units_tables et remplace la bannière du jeu
+	installedPack := buildTestPack("PFH3", ntw.PackMod, 0, []string{"data.pack"}, []testPackFile{
+		{"db/units_tables/grenadiers", "units"},
+		{"UI/Skins/Default/banner.tga", "banner"},
+	})
+	installedArchive := filepath.Join(cfg.TempPath, "grenadiers.zip")
+	writeTestZip(t, installedArchive, map[string]string{"grenadiers.pack": string(installedPack)})
+	installedMod := &models.Mod{ID: "ntw_grenadiers_1", Name: "Grenadiers", Version: "1", Game: "ntw", Slug: "grenadiers"}
+	if err := installer.InstallMod(context.Background(), installedMod, installedArchive, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// Mod prévu : modifie aussi units_tables, sous un autre nom, et la même bannière
+	plannedPack := buildTestPack("PFH3", ntw.PackMod, 0, nil, []testPackFile{
+		{"db/units_tables/hussars", "units"},
+		{"ui/skins/default/banner.tga", "banner"},
+		{"text/db/hussars.loc", "loc"},
+	})
+	plannedArchive := filepath.Join(cfg.TempPath, "hussars.zip")
+	writeTestZip(t, plannedArchive, map[string]string{"hussars.pack": string(plannedPack), "readme.txt": "readme"})
+	planned := []models.Mod{{ID: "ntw_hussars_1", Name: "Hussars", Version: "1", Game: "ntw", Slug: "hussars"}}
+
+	report, err := installer.CheckPackConflicts(planned, map[string]string{"ntw_hussars_1": plannedArchive})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Files) != 1 || report.Files[0].Path != "ui/skins/default/banner.tga" {
+		t.Fatalf("unexpected file conflicts: %+v", report.Files)
+	}
+	banner := report.Files[0]
+	if !banner.OverridesVanilla() || !banner.BetweenMods() || len(banner.Sources) != 3 {
+		t.Fatalf("unexpected banner sources: %+v", banner.Sources)
+	}
+	if banner.Sources[0].Pack != "data.pack" || banner.Sources[1].ModID != "ntw_grenadiers_1" || banner.Sources[2].Pack != "hussars.pack" {
+		t.Errorf("sources not in load order: %+v", banner.Sources)
+	}
+	if len(report.Tables) != 1 || report.Tables[0].Table != "db/units_tables" || len(report.Tables[0].Sources) != 2 {
+		t.Errorf("unexpected table conflicts: %+v", report.Tables)
+	}
+	if !report.HasModConflicts() || len(report.Warnings) != 0 {
+		t.Errorf("unexpected report: %+v", report)
+	}
+
+	// Sans mod prévu, seul le remplacement de la bannière du jeu reste ; un pack
+	// illisible est signalé sans bloquer l'analyse
+	if err := os.WriteFile(filepath.Join(installer.GetDataPath(), "broken.pack"), []byte("PFH9"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	report, err = installer.CheckPackConflicts(nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Files) != 1 || report.Files[0].BetweenMods() || report.HasModConflicts() {
+		t.Errorf("unexpected installed-only report: %+v", report)
+	}
+	if len(report.Warnings) != 1 {
+		t.Errorf("expected a warning for broken.pack, got %v", report.Warnings)
+	}
+}
@@ -0,0 +1,108 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"mod-installer/models"
+	"mod-installer/services"
+)
+
+func TestInstallQueueLimitsConcurrencyAndKeepsOrder(t *testing.T) {
+	installer, cfg := newTestInstaller(t)
+	archive := zipBytes(t)
+
+	var mu sync.Mutex
+	active, maxActive := 0, 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		active++
+		if active > maxActive {
+			maxActive = active
+		}
+		mu.Unlock()
+
+		time.Sleep(50 * time.Millisecond)
+		w.Write(archive)
+
+		mu.Lock()
+		active--
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	mods := make([]models.Mod, 5)
+	for i := range mods {
+		mods[i] = models.Mod{
+			ID:          fmt.Sprintf("queued_%d", i),
+			Name:        fmt.Sprintf("Queued %d", i),
+			Version:     "1",
+			DownloadURL: fmt.Sprintf("%s/mod%d.zip", server.URL, i),
+		}
+	}
+
+	downloader := services.NewDownloadService(cfg.TempPath, false)
+	queue := services.NewInstallQueue(downloader, installer, 2)
+
+	var completed []string
+	err := queue.Run(context.Background(), mods, func(state models.Installation, detail string) {
+		if state.Status == models.StatusCompleted {
+			completed = append(completed, state.ModID)
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if maxActive > 2 {
+		t.Fatalf("expected at most 2 concurrent downloads, got %d", maxActive)
+	}
+	if maxActive < 2 {
+		t.Fatalf("expected downloads to run in parallel, got %d", maxActive)
+	}
+	for i, id := range completed {
+		if id != mods[i].ID {
+			t.Fatalf("installs out of order: %v", completed)
+		}
+	}
+	if len(completed) != len(mods) {
+		t.Fatalf("expected %d installs, got %v", len(mods), completed)
+	}
+}
+
+func TestInstallQueueRunDownloadedSkipsDownloads(t *testing.T) {
+	installer, cfg := newTestInstaller(t)
+	archive := filepath.Join(t.TempDir(), "mod.zip")
+	writeTestZip(t, archive, map[string]string{"downloaded.pack": "pack"})
+
+	// Sans URL de téléchargement : seule l'archive fournie peut être installée
+	mod := models.Mod{ID: "downloaded_1", Name: "Downloaded", Version: "1"}
+	queue := services.NewInstallQueue(services.NewDownloadService(cfg.TempPath, false), installer, 1)
+
+	var statuses []models.Status
+	err := queue.RunDownloaded(context.Background(), []models.Mod{mod}, map[string]string{mod.ID: archive}, func(state models.Installation, detail string) {
+		statuses = append(statuses, state.Status)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, status := range statuses {
+		if status == models.StatusDownloading {
+			t.Fatalf("no download expected, got statuses %v", statuses)
+		}
+	}
+	if !fileExists(filepath.Join(installer.GetDataPath(), "downloaded.pack")) {
+		t.Fatal("the given archive should be installed")
+	}
+
+	missing := models.Mod{ID: "missing_1", Name: "Missing", Version: "1"}
+	if err := queue.RunDownloaded(context.Background(), []models.Mod{missing}, nil, nil); err == nil {
+		t.Fatal("a mod without a downloaded archive should fail")
+	}
+}
@@ -0,0 +1,114 @@
+package tests
+
+import (
+	"errors"
+	"testing"
+
+	"mod-installer/models"
+	"mod-installer/services"
+)
+
+// resolverCatalog : fcn dépend de units, qui exige lib 1.0, et de lib toutes versions ;
+// darthmod est incompatible avec fcn
+func resolverCatalog() map[string]models.Mod {
+	return map[string]models.Mod{
+		"ntw_lib_1.0":    {ID: "ntw_lib_1.0", Name: "Lib", Version: "1.0", Game: "ntw", Slug: "lib"},
+		"ntw_lib_1.1":    {ID: "ntw_lib_1.1", Name: "Lib", Version: "1.1", Game: "ntw", Slug: "lib"},
+		"ntw_units_2":    {ID: "ntw_units_2", Name: "Units", Version: "2", Game: "ntw", Slug: "units", Dependencies: []string{"ntw_lib_1.0"}},
+		"ntw_fcn_8.2.0":  {ID: "ntw_fcn_8.2.0", Name: "FCN", Version: "8.2.0", Game: "ntw", Slug: "fcn", Dependencies: []string{"ntw_units", "ntw_lib"}},
+		"ntw_darthmod_1": {ID: "ntw_darthmod_1", Name: "DarthMod", Version: "1", Game: "ntw", Slug: "darthmod", Conflicts: []string{"ntw_fcn"}},
+		"ntw_orphan_1":   {ID: "ntw_orphan_1", Name: "Orphan", Version: "1", Game: "ntw", Slug: "orphan", Dependencies: []string{"ntw_missing"}},
+		"ntw_chicken_1":  {ID: "ntw_chicken_1", Name: "Chicken", Version: "1", Game: "ntw", Slug: "chicken", Dependencies: []string{"ntw_egg"}},
+		"ntw_egg_1":      {ID: "ntw_egg_1", Name: "Egg", Version: "1", Game: "ntw", Slug: "egg", Dependencies: []string{"ntw_chicken_1"}},
+	}
+}
+
+func planIDs(plan *services.InstallPlan) []string {
+	ids := make([]string, len(plan.Steps))
+	for i, step := range plan.Steps {
+		ids[i] = step.Mod.ID
+	}
+	return ids
+}
+
+func TestResolveInstallPlanOrdersDependencies(t *testing.T) {
+	mods := resolverCatalog()
+	plan, err := services.ResolveInstallPlan(models.GroupMods(mods), []models.Mod{mods["ntw_fcn_8.2.0"]}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// lib 1.0, exigée par units, satisfait aussi la dépendance de fcn sur lib
+	ids := planIDs(plan)
+	if len(ids) != 3 || ids[0] != "ntw_lib_1.0" || ids[1] != "ntw_units_2" || ids[2] != "ntw_fcn_8.2.0" {
+		t.Fatalf("unexpected plan: %v", ids)
+	}
+	if !plan.Steps[0].Dependency || plan.Steps[2].Dependency {
+		t.Errorf("dependencies should be flagged: %+v", plan.Steps)
+	}
+	if required := plan.Steps[0].RequiredBy; len(required) != 2 {
+		t.Errorf("expected lib to be required by units and FCN, got %v", required)
+	}
+}
+
+func TestResolveInstallPlanSkipsInstalledDependencies(t *testing.T) {
+	mods := resolverCatalog()
+	installed := []*models.InstallManifest{{ModID: "ntw_lib_1.1", Version: "1.1", Group: "ntw_lib"}}
+
+	plan, err := services.ResolveInstallPlan(models.GroupMods(mods), []models.Mod{mods["ntw_units_2"]}, installed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// units exige précisément lib 1.0 : la version installée ne convient pas
+	if ids := planIDs(plan); len(ids) != 2 || ids[0] != "ntw_lib_1.0" {
+		t.Errorf("expected lib 1.0 to be pulled in, got %v", ids)
+	}
+
+	plan, err = services.ResolveInstallPlan(models.GroupMods(mods), []models.Mod{{ID: "ntw_addon_1", Game: "ntw", Slug: "addon", Dependencies: []string{"ntw_lib"}}}, installed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ids := planIDs(plan); len(ids) != 1 {
+		t.Errorf("installed lib should satisfy the dependency, got %v", ids)
+	}
+}
+
+func TestResolveInstallPlanRejectsConflicts(t *testing.T) {
+	mods := resolverCatalog()
+	catalog := models.GroupMods(mods)
+	var conflict *services.ConflictError
+
+	_, err := services.ResolveInstallPlan(catalog, []models.Mod{mods["ntw_fcn_8.2.0"], mods["ntw_darthmod_1"]}, nil)
+	if !errors.As(err, &conflict) || conflict.Installed {
+		t.Errorf("expected a conflict within the plan, got %v", err)
+	}
+
+	// Conflit déclaré par le mod déjà installé
+	installed := []*models.InstallManifest{{ModID: "ntw_darthmod_1", Version: "1", Group: "ntw_darthmod"}}
+	_, err = services.ResolveInstallPlan(catalog, []models.Mod{mods["ntw_fcn_8.2.0"]}, installed)
+	if !errors.As(err, &conflict) || !conflict.Installed || conflict.OtherID != "ntw_darthmod_1" {
+		t.Errorf("expected a conflict with the installed mod, got %v", err)
+	}
+
+	// Deux versions d'un même mod
+	_, err = services.ResolveInstallPlan(catalog, []models.Mod{mods["ntw_units_2"], mods["ntw_lib_1.1"]}, nil)
+	if !errors.As(err, &conflict) {
+		t.Errorf("expected two versions of lib to conflict, got %v", err)
+	}
+}
+
+func TestResolveInstallPlanReportsMissingAndCycles(t *testing.T) {
+	mods := resolverCatalog()
+	catalog := models.GroupMods(mods)
+
+	var missing *services.MissingDependencyError
+	if _, err := services.ResolveInstallPlan(catalog, []models.Mod{mods["ntw_orphan_1"]}, nil); !errors.As(err, &missing) || missing.Dependency != "ntw_missing" {
+		t.Errorf("expected a missing dependency, got %v", err)
+	}
+
+	var cycle *services.CycleError
+	_, err := services.ResolveInstallPlan(catalog, []models.Mod{mods["ntw_chicken_1"]}, nil)
+	if !errors.As(err, &cycle) || len(cycle.Cycle) != 3 || cycle.Cycle[0] != cycle.Cycle[2] {
+		t.Errorf("expected a dependency cycle, got %v", err)
+	}
+}
@@ -0,0 +1,161 @@
+package tests
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"mod-installer/api"
+	"mod-installer/models"
+)
+
+func TestParseLegacyModMeta(t *testing.T) {
+	mod, err := api.ParseModMeta([]byte(`{
+		"metadata": {"link": "https://example.com/fcn.zip", "size": "120", "day": "03/12/2023"},
+		"installation": ["Extraire dans le dossier data", "FCN/data -> data"]
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mod.DownloadURL != "https://example.com/fcn.zip" || mod.FileSize != 120*1024*1024 {
+		t.Errorf("unexpected link or size: %+v", mod)
+	}
+	if mod.CreatedAt.Month() != 3 || mod.CreatedAt.Day() != 12 {
+		t.Errorf("expected MM/DD/YYYY date, got %v", mod.CreatedAt)
+	}
+	want := []models.InstallRule{{From: "FCN/data", To: models.InstallTargetData}}
+	if !reflect.DeepEqual(mod.Installation, want) {
+		t.Errorf("expected only the structured directive to be kept, got %+v", mod.Installation)
+	}
+}
+
+func TestParseCurrentModMeta(t *testing.T) {
+	mod, err := api.ParseModMeta([]byte(`{
+		"schema_version": 2,
+		"id": "ntw_fcn",
+		"name": "FCN",
+		"version": "8.2.0",
+		"description": "Overhaul",
+		"author": "FCN team",
+		"category": "overhaul",
+		"tags": ["units", "campaign"],
+		"metadata": {
+			"link": "https://example.com/fcn.zip",
+			"size": 120,
+			"day": "03/12/2023",
+			"updated": "04/01/2023",
+			"checksum": "ABCDEF",
+			"mirrors": ["https://mirror.example.com/fcn.zip"]
+		},
+		"dependencies": ["ntw_base"],
+		"conflicts": ["ntw_other"],
+		"installation": [
+			{"from": "FCN/data/", "to": "data"},
+			{"from": "FCN\\scripts", "to": "Scripts"}
+		]
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if mod.ID != "ntw_fcn" || mod.Name != "FCN" || mod.Version != "8.2.0" || mod.Author != "FCN team" || mod.Category != "overhaul" {
+		t.Errorf("identity fields not mapped: %+v", mod)
+	}
+	if mod.Checksum != "abcdef" || mod.FileSize != 120*1024*1024 || mod.UpdatedAt.Month() != 4 {
+		t.Errorf("metadata not mapped: %+v", mod)
+	}
+	if !reflect.DeepEqual(mod.Tags, []string{"units", "campaign"}) || !reflect.DeepEqual(mod.Dependencies, []string{"ntw_base"}) || !reflect.DeepEqual(mod.Conflicts, []string{"ntw_other"}) {
+		t.Errorf("lists not mapped: %+v", mod)
+	}
+	if len(mod.Mirrors) != 1 || mod.Mirrors[0].URL != "https://mirror.example.com/fcn.zip" {
+		t.Errorf("mirrors not mapped: %+v", mod.Mirrors)
+	}
+	want := []models.InstallRule{
+		{From: "FCN/data", To: models.InstallTargetData},
+		{From: "FCN/scripts", To: models.InstallTargetScripts},
+	}
+	if !reflect.DeepEqual(mod.Installation, want) {
+		t.Errorf("unexpected installation rules: %+v", mod.Installation)
+	}
+}
+
+func TestParseModMetaRejectsInvalidEntries(t *testing.T) {
+	cases := map[string]string{
+		"future schema":   `{"schema_version": 3, "metadata": {}}`,
+		"bad target":      `{"schema_version": 2, "metadata": {}, "installation": [{"from": "x", "to": "movies"}]}`,
+		"traversal":       `{"schema_version": 2, "metadata": {}, "installation": [{"from": "../x", "to": "data"}]}`,
+		"free text in v2": `{"schema_version": 2, "metadata": {}, "installation": ["copier les fichiers"]}`,
+	}
+	for name, data := range cases {
+		if _, err := api.ParseModMeta([]byte(data)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestInstallRulesRouteArchiveFolders(t *testing.T) {
+	installer, cfg := newTestInstaller(t)
+	archive := filepath.Join(cfg.TempPath, "ruled.zip")
+	writeTestZip(t, archive, map[string]string{
+		"FCN/data/units.pack":          "pack",
+		"FCN/data/sub/models.pack":     "pack",
+		"FCN/scripts/user.script.txt":  "script",
+		"FCN/readme.txt":               "readme",
+		"FCN/screenshots/campaign.png": "png",
+	})
+
+	mod := &models.Mod{ID: "ruled", Name: "Ruled", Version: "1", Installation: []models.InstallRule{
+		{From: "FCN/data", To: models.InstallTargetData},
+		{From: "FCN/scripts", To: models.InstallTargetScripts},
+	}}
+	if err := installer.InstallMod(context.Background(), mod, archive, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, path := range []string{
+		filepath.Join(installer.GetDataPath(), "units.pack"),
+		filepath.Join(installer.GetDataPath(), "sub", "models.pack"),
+		filepath.Join(installer.GetScriptsPath(), "user.script.txt"),
+	} {
+		if !fileExists(path) {
+			t.Errorf("expected %s to be installed", path)
+		}
+	}
+	for _, path := range []string{
+		filepath.Join(installer.GetScriptsPath(), "FCN", "readme.txt"),
+		filepath.Join(installer.GetDataPath(), "FCN"),
+	} {
+		if fileExists(path) {
+			t.Errorf("%s should not be installed", path)
+		}
+	}
+
+	manifest, err := installer.GetInstallManifest(mod)
+	if err != nil || manifest == nil || len(manifest.Files) != 3 {
+		t.Fatalf("expected 3 files in the manifest, got %+v (%v)", manifest, err)
+	}
+}
+
+func TestInstallRulesDoNotClobberUnprocessedFiles(t *testing.T) {
+	installer, cfg := newTestInstaller(t)
+	archive := filepath.Join(cfg.TempPath, "clobber.zip")
+	writeTestZip(t, archive, map[string]string{
+		"FCN/units.pack": "from FCN",
+		"units.pack":     "from root",
+	})
+
+	// Le fichier de FCN prend la place du units.pack de la racine, qu'aucune règle ne couvre
+	mod := &models.Mod{ID: "clobber", Name: "Clobber", Version: "1", Installation: []models.InstallRule{
+		{From: "FCN", To: models.InstallTargetData},
+	}}
+	if err := installer.InstallMod(context.Background(), mod, archive, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(installer.GetDataPath(), "units.pack"))
+	if err != nil || string(data) != "from FCN" {
+		t.Errorf("expected the FCN copy of units.pack, got %q (%v)", data, err)
+	}
+}
@@ -0,0 +1,176 @@
+package tests
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"mod-installer/models"
+	"mod-installer/services"
+)
+
+// newSourceClient retourne un client HTTP dont toutes les requêtes arrivent sur handler
+func newSourceClient(t *testing.T, handler http.HandlerFunc) *http.Client {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	target, _ := url.Parse(server.URL)
+	return &http.Client{Transport: redirectTransport{target: target}}
+}
+
+func readResolved(t *testing.T, resolved *services.ResolvedDownload) string {
+	t.Helper()
+	defer resolved.Close()
+	data, err := io.ReadAll(resolved.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func serveMediafireFile(w http.ResponseWriter, r *http.Request) bool {
+	if r.Host != "download2391.mediafire.com" || r.URL.Path != "/abc123/x7k2m9/Napoleon_Units_v2.zip" {
+		return false
+	}
+	w.Header().Set("Content-Type", "application/zip")
+	w.Write([]byte("archive"))
+	return true
+}
+
+func TestSourcesMatchTheirHosts(t *testing.T) {
+	client := http.DefaultClient
+	cases := []struct {
+		source services.DownloadSource
+		url    string
+		want   bool
+	}{
+		{services.NewGoogleDriveSource(client), "https://drive.google.com/file/d/abc/view", true},
+		{services.NewGoogleDriveSource(client), "https://drive.google.com/drive/folders/abc", false},
+		{services.NewMediafireSource(client), "https://www.mediafire.com/file/x7k2m9/Napoleon_Units_v2.zip/file", true},
+		{services.NewMediafireSource(client), "https://notmediafire.com/file", false},
+		{services.NewModDBSource(client), "https://www.moddb.com/mods/napoleon/downloads/units-v2", true},
+		{services.NewDirectSource(client), "https://example.com/mod.zip", true},
+		{services.NewDirectSource(client), "ftp://example.com/mod.zip", false},
+	}
+	for _, c := range cases {
+		if got := c.source.Matches(c.url); got != c.want {
+			t.Errorf("%s.Matches(%s) = %v, want %v", c.source.Name(), c.url, got, c.want)
+		}
+	}
+}
+
+func TestMediafireLandingPageIsResolved(t *testing.T) {
+	for _, fixture := range []string{"mediafire_file.html", "mediafire_scrambled.html"} {
+		t.Run(fixture, func(t *testing.T) {
+			client := newSourceClient(t, func(w http.ResponseWriter, r *http.Request) {
+				if serveMediafireFile(w, r) {
+					return
+				}
+				if r.Host == "www.mediafire.com" {
+					serveFixture(t, w, fixture)
+					return
+				}
+				http.NotFound(w, r)
+			})
+
+			resolved, err := services.NewMediafireSource(client).Resolve(context.Background(), "https://www.mediafire.com/file/x7k2m9/Napoleon_Units_v2.zip/file")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resolved.FileName != "Napoleon_Units_v2.zip" {
+				t.Errorf("unexpected file name %q", resolved.FileName)
+			}
+			if body := readResolved(t, resolved); body != "archive" {
+				t.Errorf("unexpected body %q", body)
+			}
+		})
+	}
+}
+
+func TestMediafireMissingButtonIsReported(t *testing.T) {
+	client := newSourceClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte("<!DOCTYPE html><html><body>File removed</body></html>"))
+	})
+
+	_, err := services.NewMediafireSource(client).Resolve(context.Background(), "https://www.mediafire.com/file/gone/file")
+	if err == nil || !strings.Contains(err.Error(), "introuvable") {
+		t.Fatalf("expected a missing button error, got %v", err)
+	}
+}
+
+func TestModDBFallsBackToNextMirror(t *testing.T) {
+	var mirrors []string
+	client := newSourceClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/mods/napoleon/downloads/units-v2":
+			serveFixture(t, w, "moddb_download.html")
+		case r.URL.Path == "/downloads/start/245871":
+			serveFixture(t, w, "moddb_start.html")
+		case strings.HasPrefix(r.URL.Path, "/downloads/mirror/245871/130/"):
+			mirrors = append(mirrors, "130")
+			http.Error(w, "mirror down", http.StatusServiceUnavailable)
+		case strings.HasPrefix(r.URL.Path, "/downloads/mirror/245871/131/"):
+			mirrors = append(mirrors, "131")
+			w.Header().Set("Content-Type", "application/zip")
+			w.Header().Set("Content-Disposition", `attachment; filename="napoleon_units_v2.zip"`)
+			w.Write([]byte("archive"))
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	resolved, err := services.NewModDBSource(client).Resolve(context.Background(), "https://www.moddb.com/mods/napoleon/downloads/units-v2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resolved.FileName != "napoleon_units_v2.zip" {
+		t.Errorf("unexpected file name %q", resolved.FileName)
+	}
+	if body := readResolved(t, resolved); body != "archive" {
+		t.Errorf("unexpected body %q", body)
+	}
+	if strings.Join(mirrors, ",") != "130,131" {
+		t.Errorf("expected mirrors to be tried in order, got %v", mirrors)
+	}
+}
+
+// staticSource sert un contenu fixe pour toutes les URL d'un schéma donné
+type staticSource struct {
+	content string
+}
+
+func (s staticSource) Name() string               { return "static" }
+func (s staticSource) Matches(rawURL string) bool { return strings.HasPrefix(rawURL, "static://") }
+func (s staticSource) Resolve(ctx context.Context, rawURL string) (*services.ResolvedDownload, error) {
+	return &services.ResolvedDownload{
+		Body:     io.NopCloser(strings.NewReader(s.content)),
+		FileName: "static.zip",
+		Size:     int64(len(s.content)),
+		Response: &http.Response{StatusCode: http.StatusOK, Header: http.Header{}},
+	}, nil
+}
+
+func TestRegisteredSourceIsUsedByDownloadMod(t *testing.T) {
+	ds := services.NewDownloadService(t.TempDir(), false)
+	ds.RegisterSource(staticSource{content: string(zipBytes(t))})
+
+	mod := &models.Mod{ID: "static_mod", Version: "1", DownloadURL: "static://units"}
+	path, err := ds.DownloadMod(context.Background(), mod, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(path, ".zip") {
+		t.Fatalf("expected a cached zip, got %s", path)
+	}
+
+	unknown := &models.Mod{ID: "unknown", Version: "1", DownloadURL: "gopher://example.com/mod"}
+	if _, err := ds.DownloadMod(context.Background(), unknown, nil); err == nil {
+		t.Fatal("expected an error for a URL no source accepts")
+	}
+}
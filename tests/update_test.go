@@ -0,0 +1,226 @@
+package tests
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"mod-installer/config"
+	"mod-installer/models"
+	"mod-installer/services"
+)
+
+func fcnVersion(version string) models.Mod {
+	return models.Mod{ID: "ntw_fcn_" + version, Name: "FCN", Version: version, Game: "ntw", Slug: "fcn"}
+}
+
+// incompressible retourne un contenu que la compression ZIP ne réduit pas
+func incompressible(size int) string {
+	data := make([]byte, size)
+	rand.New(rand.NewSource(1)).Read(data)
+	return string(data)
+}
+
+func TestFindUpdates(t *testing.T) {
+	catalog := models.GroupMods(map[string]models.Mod{
+		"ntw_fcn_8.2.0":      fcnVersion("8.2.0"),
+		"ntw_fcn_8.2.1":      fcnVersion("8.2.1"),
+		"ntw_fcn_9.0.0-rc.1": fcnVersion("9.0.0-rc.1"),
+		"ntw_fcn_9.0.0-rc.2": fcnVersion("9.0.0-rc.2"),
+		"ntw_dm_1":           {ID: "ntw_dm_1", Name: "DM", Version: "1", Game: "ntw", Slug: "dm"},
+	})
+
+	installed := []*models.InstallManifest{
+		{ModID: "ntw_fcn_8.2.0", Version: "8.2.0", Group: "ntw_fcn"},
+		{ModID: "ntw_dm_1", Version: "1"},               // À jour, enregistré sans clé de mod
+		{ModID: "ntw_gone_1", Version: "1", Group: "x"}, // Retiré du catalogue
+	}
+	updates := services.FindUpdates(catalog, installed)
+	if len(updates) != 1 || updates[0].Latest.ID != "ntw_fcn_8.2.1" || updates[0].Installed.ModID != "ntw_fcn_8.2.0" {
+		t.Fatalf("expected the stable 8.2.1 update, got %+v", updates)
+	}
+
+	// Une préversion installée suit les préversions
+	updates = services.FindUpdates(catalog, []*models.InstallManifest{{ModID: "ntw_fcn_9.0.0-rc.1", Version: "9.0.0-rc.1"}})
+	if len(updates) != 1 || updates[0].Latest.Version != "9.0.0-rc.2" {
+		t.Fatalf("expected the newer release candidate, got %+v", updates)
+	}
+}
+
+func TestUpdateReplacesInstalledVersion(t *testing.T) {
+	installer, cfg := newTestInstaller(t)
+	dataPath := installer.GetDataPath()
+
+	oldArchive := filepath.Join(cfg.TempPath, "fcn_8.2.0.zip")
+	writeTestZip(t, oldArchive, map[string]string{"fcn/units.pack": "old", "fcn/legacy.pack": "legacy"})
+	oldMod := fcnVersion("8.2.0")
+	if err := installer.InstallMod(context.Background(), &oldMod, oldArchive, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	newArchive := filepath.Join(t.TempDir(), "fcn_8.2.1.zip")
+	writeTestZip(t, newArchive, map[string]string{"fcn/units.pack": "new", "fcn/models.pack": incompressible(4096)})
+	archive, err := os.ReadFile(newArchive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/zip")
+		w.Write(archive)
+	}))
+	defer server.Close()
+
+	newMod := fcnVersion("8.2.1")
+	newMod.DownloadURL = server.URL + "/fcn.zip"
+	updates, err := installer.AvailableUpdates(models.GroupMods(map[string]models.Mod{oldMod.ID: oldMod, newMod.ID: newMod}))
+	if err != nil || len(updates) != 1 {
+		t.Fatalf("expected one update, got %+v, %v", updates, err)
+	}
+
+	queue := services.NewInstallQueue(services.NewDownloadService(cfg.TempPath, false), installer, 1)
+	if err := queue.Update(context.Background(), updates, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if content, _ := os.ReadFile(filepath.Join(dataPath, "fcn", "units.pack")); string(content) != "new" {
+		t.Errorf("expected the new version's files, got %q", content)
+	}
+	if fileExists(filepath.Join(dataPath, "fcn", "legacy.pack")) {
+		t.Error("files of the previous version should be removed")
+	}
+	if manifest, _ := installer.GetInstallManifest(&oldMod); manifest != nil {
+		t.Error("the previous version should no longer be recorded")
+	}
+	if installed, err := installer.GetInstallationStatus(&newMod); !installed || err != nil {
+		t.Errorf("new version should be installed, got %v, %v", installed, err)
+	}
+}
+
+func TestCatalogRefreshReportsChanges(t *testing.T) {
+	cfg := config.Default()
+	cfg.ConfigPath = filepath.Join(t.TempDir(), "config.json")
+
+	catalog := map[string]models.Mod{"ntw_fcn_8.2.0": fcnVersion("8.2.0"), "ntw_fcn_8.1.0": fcnVersion("8.1.0")}
+	cs := newTestCatalogService(t, cfg, func() (map[string]models.Mod, error) { return catalog, nil })
+	if state := cs.Refresh(); !state.Changes.Empty() {
+		t.Fatalf("the first catalog should not be reported as changes, got %+v", state.Changes)
+	}
+
+	catalog = map[string]models.Mod{
+		"ntw_fcn_8.2.0": fcnVersion("8.2.0"),
+		"ntw_fcn_8.2.1": fcnVersion("8.2.1"),
+		"ntw_dm_1":      {ID: "ntw_dm_1", Name: "DM", Version: "1", Game: "ntw", Slug: "dm"},
+	}
+	changes := cs.Refresh().Changes
+	if len(changes.NewVersions) != 1 || changes.NewVersions[0].ID != "ntw_fcn_8.2.1" {
+		t.Errorf("expected FCN 8.2.1 as a new version, got %+v", changes.NewVersions)
+	}
+	if len(changes.NewMods) != 1 || changes.NewMods[0].ID != "ntw_dm_1" {
+		t.Errorf("expected DM as a new mod, got %+v", changes.NewMods)
+	}
+	if len(changes.Removed) != 1 || changes.Removed[0].ID != "ntw_fcn_8.1.0" {
+		t.Errorf("expected FCN 8.1.0 as removed, got %+v", changes.Removed)
+	}
+}
+
+func TestFailedUpdateKeepsInstalledVersion(t *testing.T) {
+	installer, cfg := newTestInstaller(t)
+	dataPath := installer.GetDataPath()
+
+	oldArchive := filepath.Join(cfg.TempPath, "fcn_8.2.0.zip")
+	writeTestZip(t, oldArchive, map[string]string{"fcn/units.pack": "old", "fcn/legacy.pack": "legacy"})
+	oldMod := fcnVersion("8.2.0")
+	if err := installer.InstallMod(context.Background(), &oldMod, oldArchive, nil); err != nil {
+		t.Fatal(err)
+	}
+	oldManifest, err := installer.GetInstallManifest(&oldMod)
+	if err != nil || oldManifest == nil {
+		t.Fatalf("expected a manifest for the installed version, got %v", err)
+	}
+
+	// La nouvelle version ne contient aucun fichier : elle échoue à la validation
+	var archive bytes.Buffer
+	w := zip.NewWriter(&archive)
+	if _, err := w.Create("fcn/"); err != nil {
+		t.Fatal(err)
+	}
+	w.SetComment(strings.Repeat("-", 2048)) // Le téléchargement refuse les fichiers trop petits
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/zip")
+		w.Write(archive.Bytes())
+	}))
+	defer server.Close()
+
+	newMod := fcnVersion("8.2.1")
+	newMod.DownloadURL = server.URL + "/fcn.zip"
+	queue := services.NewInstallQueue(services.NewDownloadService(cfg.TempPath, false), installer, 1)
+	err = queue.Update(context.Background(), []services.ModUpdate{{Installed: oldManifest, Latest: newMod}}, nil)
+	if err == nil {
+		t.Fatal("expected the update to fail")
+	}
+
+	for name, want := range map[string]string{"units.pack": "old", "legacy.pack": "legacy"} {
+		if content, _ := os.ReadFile(filepath.Join(dataPath, "fcn", name)); string(content) != want {
+			t.Errorf("%s of the installed version should be kept, got %q", name, content)
+		}
+	}
+	if installed, err := installer.GetInstallationStatus(&oldMod); !installed || err != nil {
+		t.Errorf("installed version should still be recorded, got %v, %v", installed, err)
+	}
+	if manifest, _ := installer.GetInstallManifest(&newMod); manifest != nil {
+		t.Error("the failed version should not be recorded")
+	}
+}
+
+func TestUpdateKeepsOriginalGameFileBackup(t *testing.T) {
+	installer, cfg := newTestInstaller(t)
+	unitsPath := filepath.Join(installer.GetDataPath(), "units.pack")
+	if err := os.WriteFile(unitsPath, []byte("original"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	oldArchive := filepath.Join(cfg.TempPath, "fcn_8.2.0.zip")
+	writeTestZip(t, oldArchive, map[string]string{"units.pack": "old"})
+	oldMod := fcnVersion("8.2.0")
+	if err := installer.InstallMod(context.Background(), &oldMod, oldArchive, nil); err != nil {
+		t.Fatal(err)
+	}
+	oldManifest, _ := installer.GetInstallManifest(&oldMod)
+
+	newArchive := filepath.Join(t.TempDir(), "fcn_8.2.1.zip")
+	writeTestZip(t, newArchive, map[string]string{"units.pack": "new", "models.pack": incompressible(4096)})
+	archive, err := os.ReadFile(newArchive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/zip")
+		w.Write(archive)
+	}))
+	defer server.Close()
+
+	newMod := fcnVersion("8.2.1")
+	newMod.DownloadURL = server.URL + "/fcn.zip"
+	queue := services.NewInstallQueue(services.NewDownloadService(cfg.TempPath, false), installer, 1)
+	if err := queue.Update(context.Background(), []services.ModUpdate{{Installed: oldManifest, Latest: newMod}}, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// La désinstallation de la nouvelle version rend le fichier du jeu, pas l'ancienne version
+	if _, err := installer.UninstallMod(&newMod, false); err != nil {
+		t.Fatal(err)
+	}
+	if content, _ := os.ReadFile(unitsPath); string(content) != "original" {
+		t.Errorf("expected the original game file, got %q", content)
+	}
+}
@@ -0,0 +1,102 @@
+package tests
+
+import (
+	"strings"
+	"testing"
+
+	"mod-installer/api"
+)
+
+// diagnosticsFor retourne les diagnostics d'un champ donné
+func diagnosticsFor(diagnostics []api.Diagnostic, path, field string) []api.Diagnostic {
+	found := make([]api.Diagnostic, 0)
+	for _, d := range diagnostics {
+		if d.Path == path && d.Field == field {
+			found = append(found, d)
+		}
+	}
+	return found
+}
+
+func TestValidateModMetaReportsInvalidFields(t *testing.T) {
+	cases := []struct {
+		name  string
+		data  string
+		field string
+	}{
+		{"missing link", `{"metadata": {"size": "10", "day": "03/12/2023"}}`, "metadata.link"},
+		{"missing size", `{"metadata": {"link": "https://mods.org/a.zip", "day": "03/12/2023"}}`, "metadata.size"},
+		{"missing day", `{"metadata": {"link": "https://mods.org/a.zip", "size": "10"}}`, "metadata.day"},
+		{"bad date", `{"metadata": {"link": "https://mods.org/a.zip", "size": "10", "day": "2023-03-12"}}`, "metadata.day"},
+		{"fractional size", `{"metadata": {"link": "https://mods.org/a.zip", "size": "12.5", "day": "03/12/2023"}}`, "metadata.size"},
+		{"unsupported scheme", `{"metadata": {"link": "ftp://mods.org/a.zip", "size": "10", "day": "03/12/2023"}}`, "metadata.link"},
+		{"not an archive", `{"metadata": {"link": "https://mods.org/setup.exe", "size": "10", "day": "03/12/2023"}}`, "metadata.link"},
+		{"bad checksum", `{"metadata": {"link": "https://mods.org/a.zip", "size": "10", "day": "03/12/2023", "checksum": "abc"}}`, "metadata.checksum"},
+		{"bad v2 directive", `{"schema_version": 2, "metadata": {"link": "https://mods.org/a.zip", "size": 10, "day": "03/12/2023"}, "installation": [{"from": "x", "to": "bin"}]}`, "installation[0]"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			diagnostics := api.ValidateModMeta("ntw/mod/1.json", []byte(c.data))
+			found := diagnosticsFor(diagnostics, "ntw/mod/1.json", c.field)
+			if len(found) != 1 || found[0].Severity != api.SeverityError {
+				t.Errorf("expected one error on %s, got %v", c.field, diagnostics)
+			}
+		})
+	}
+}
+
+func TestValidateModMetaAcceptsHostPages(t *testing.T) {
+	data := `{"metadata": {"link": "https://www.mediafire.com/file/abc/Mod_v2.zip/file", "size": "10", "day": "03/12/2023",
+		"mirrors": ["https://drive.google.com/file/d/abc/view", "https://www.moddb.com/mods/x/downloads/download.php"]}}`
+	if diagnostics := api.ValidateModMeta("ntw/mod/1.json", []byte(data)); len(diagnostics) != 0 {
+		t.Errorf("expected no diagnostics, got %v", diagnostics)
+	}
+}
+
+func TestValidateCatalogCrossReferences(t *testing.T) {
+	files := map[string]string{
+		"ntw/base/1.json":  `{"schema_version": 2, "id": "base", "metadata": {"link": "https://mods.org/base.zip", "size": 10, "day": "03/12/2023"}}`,
+		"ntw/addon/1.json": `{"schema_version": 2, "metadata": {"link": "https://mods.org/addon.zip", "size": 10, "day": "03/12/2023"}, "dependencies": ["base", "ntw_missing"], "conflicts": ["ntw_base"]}`,
+		"ntw/clone/1.json": `{"schema_version": 2, "id": "base", "metadata": {"link": "https://mods.org/clone.zip", "size": 10, "day": "03/12/2023"}}`,
+	}
+	diagnostics, err := api.ValidateCatalog(writeMetaTree(t, files))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !api.HasErrors(diagnostics) {
+		t.Fatal("expected errors")
+	}
+
+	// Le dossier du mod (ntw_base) est une référence valide, pas ntw_missing
+	if found := diagnosticsFor(diagnostics, "ntw/addon/1.json", "dependencies[1]"); len(found) != 1 || !strings.Contains(found[0].Message, "ntw_missing") {
+		t.Errorf("expected the dangling dependency to be reported, got %v", diagnostics)
+	}
+	if found := diagnosticsFor(diagnostics, "ntw/addon/1.json", "dependencies[0]"); len(found) != 0 {
+		t.Errorf("known dependency reported: %v", found)
+	}
+	if found := diagnosticsFor(diagnostics, "ntw/addon/1.json", "conflicts[0]"); len(found) != 0 {
+		t.Errorf("known conflict reported: %v", found)
+	}
+	for _, path := range []string{"ntw/base/1.json", "ntw/clone/1.json"} {
+		if found := diagnosticsFor(diagnostics, path, "id"); len(found) != 1 {
+			t.Errorf("expected a duplicate ID error on %s, got %v", path, diagnostics)
+		}
+	}
+}
+
+func TestValidateCatalogCleanTree(t *testing.T) {
+	diagnostics, err := api.ValidateCatalog(writeMetaTree(t, testMetaFiles))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if api.HasErrors(diagnostics) {
+		t.Errorf("expected no errors, got %v", diagnostics)
+	}
+	// Les liens example.com des fixtures sont signalés, sans bloquer
+	for _, d := range diagnostics {
+		if d.Severity != api.SeverityWarning || d.Path == "" {
+			t.Errorf("unexpected diagnostic: %v", d)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package tests
+
+import (
+	"testing"
+
+	"mod-installer/api"
+	"mod-installer/models"
+)
+
+func TestCompareVersions(t *testing.T) {
+	cases := []struct {
+		a, b string
+		want int
+	}{
+		{"8.2.0", "8.10.0", -1},
+		{"8.2", "8.2.0", 0},
+		{"v3.1", "3.0.9", 1},
+		{"1.0.0-beta", "1.0.0", -1},
+		{"1.0.0-beta.2", "1.0.0-beta.11", -1},
+		{"1.0.0-alpha", "1.0.0-1", 1},
+		{"1.0.0+build5", "1.0.0", 0},
+		{"final", "2.0", -1},
+		{"beta", "alpha", 1},
+	}
+	for _, c := range cases {
+		if got := models.CompareVersions(c.a, c.b); got != c.want {
+			t.Errorf("CompareVersions(%q, %q) = %d, want %d", c.a, c.b, got, c.want)
+		}
+		if got := models.CompareVersions(c.b, c.a); got != -c.want {
+			t.Errorf("CompareVersions(%q, %q) = %d, want %d", c.b, c.a, got, -c.want)
+		}
+	}
+}
+
+func TestGroupModsOrdersVersions(t *testing.T) {
+	mods := map[string]models.Mod{
+		"ntw_fcn_8.2.0":      {ID: "ntw_fcn_8.2.0", Game: "ntw", Slug: "fcn", Version: "8.2.0"},
+		"ntw_fcn_8.10.0":     {ID: "ntw_fcn_8.10.0", Game: "ntw", Slug: "fcn", Version: "8.10.0"},
+		"ntw_fcn_9.0.0-beta": {ID: "ntw_fcn_9.0.0-beta", Game: "ntw", Slug: "fcn", Version: "9.0.0-beta"},
+		"ntw_darthmod_1":     {ID: "ntw_darthmod_1", Game: "ntw", Slug: "darthmod", Version: "1"},
+		"vanilla_pack":       {ID: "vanilla_pack", Version: "original"},
+	}
+	catalog := models.GroupMods(mods)
+
+	fcn := catalog["ntw"]["fcn"]
+	if got := fcn.Versions(); len(got) != 3 || got[0] != "9.0.0-beta" || got[1] != "8.10.0" || got[2] != "8.2.0" {
+		t.Fatalf("unexpected version order: %v", got)
+	}
+	if latest := fcn.Latest(); latest == nil || latest.Version != "8.10.0" {
+		t.Errorf("expected the latest stable version, got %+v", latest)
+	}
+	if mod := fcn.Find("8.2.0"); mod == nil || mod.ID != "ntw_fcn_8.2.0" {
+		t.Errorf("expected to find version 8.2.0, got %+v", mod)
+	}
+
+	groups := catalog.Groups()
+	if len(groups) != 3 || groups[0].Key() != "vanilla_pack" || groups[1].Key() != "ntw_darthmod" || groups[2].Key() != "ntw_fcn" {
+		t.Errorf("unexpected groups: %+v", groups)
+	}
+	if mod := catalog.Find("ntw_darthmod_1"); mod == nil || mod.GroupKey() != "ntw_darthmod" {
+		t.Errorf("expected to find darthmod by ID, got %+v", mod)
+	}
+}
+
+func TestLatestFallsBackToPrerelease(t *testing.T) {
+	versions := models.GroupMods(map[string]models.Mod{
+		"a": {ID: "a", Game: "ntw", Slug: "new", Version: "1.0.0-rc.1"},
+		"b": {ID: "b", Game: "ntw", Slug: "new", Version: "1.0.0-rc.2"},
+	})["ntw"]["new"]
+	if latest := versions.Latest(); latest == nil || latest.ID != "b" {
+		t.Errorf("expected the newest prerelease, got %+v", latest)
+	}
+}
+
+func TestLocalCatalogGroupsVersions(t *testing.T) {
+	files := map[string]string{
+		"ntw/fcn/8.2.0.json":  testMetaFiles["ntw/fcn/8.2.0.json"],
+		"ntw/fcn/8.10.0.json": testMetaFiles["ntw/fcn/8.2.0.json"],
+		"ntw/darthmod/1.json": testMetaFiles["ntw/darthmod/1.json"],
+	}
+	mods, err := api.NewLocalCatalog(writeMetaTree(t, files)).FetchAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	catalog := models.GroupMods(mods)
+	if len(catalog["ntw"]) != 2 || len(catalog["ntw"]["fcn"]) != 2 {
+		t.Fatalf("expected fcn versions grouped under ntw, got %+v", catalog)
+	}
+	if latest := catalog["ntw"]["fcn"].Latest(); latest.ID != "ntw_fcn_8.10.0" {
+		t.Errorf("unexpected latest version: %s", latest.ID)
+	}
+}
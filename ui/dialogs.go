@@ -1 +1,176 @@
 package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"mod-installer/services"
+)
+
+// showFileConflicts demande, pour chaque fichier écrit par plusieurs mods, quel mod
+// garde sa copie (le dernier installé par défaut). onDone reçoit les choix par
+// fichier, ou nil si l'installation est annulée.
+func showFileConflicts(parent fyne.Window, conflicts []services.FileConflict, onDone func(winners map[string]string)) {
+	winners := make(map[string]string, len(conflicts))
+	rows := container.NewVBox()
+	for _, conflict := range conflicts {
+		labels := make([]string, len(conflict.Owners))
+		modIDs := make(map[string]string, len(conflict.Owners))
+		for i, owner := range conflict.Owners {
+			label := owner.Name
+			if _, taken := modIDs[label]; taken || label == "" {
+				label = fmt.Sprintf("%s (%s)", owner.Name, owner.ModID)
+			}
+			if owner.Installed {
+				label += " - installed"
+			}
+			labels[i] = label
+			modIDs[label] = owner.ModID
+		}
+
+		path := conflict.Path
+		winners[path] = conflict.DefaultWinner()
+		winner := widget.NewSelect(labels, func(label string) {
+			winners[path] = modIDs[label]
+		})
+		winner.SetSelected(labels[len(labels)-1])
+		rows.Add(container.NewBorder(nil, nil, nil, winner, widget.NewLabel(path)))
+	}
+
+	scroll := container.NewVScroll(rows)
+	scroll.SetMinSize(fyne.NewSize(560, 320))
+	content := container.NewBorder(widget.NewLabel("These files are shipped by several mods. Choose which copy to keep:"), nil, nil, nil, scroll)
+
+	dialog.ShowCustomConfirm(fmt.Sprintf("File conflicts (%d)", len(conflicts)), "Install", "Cancel", content, func(confirmed bool) {
+		if !confirmed {
+			onDone(nil)
+			return
+		}
+		onDone(winners)
+	}, parent)
+}
+
+// showPackConflicts présente l'analyse du contenu des packs. Avec confirm, le dialogue
+// demande de poursuivre l'installation et onDone reçoit la réponse ; sans confirm, il
+// est seulement informatif.
+func showPackConflicts(parent fyne.Window, report *services.PackConflictReport, confirm string, onDone func(confirmed bool)) {
+	text := widget.NewLabel(formatPackConflicts(report))
+	text.Wrapping = fyne.TextWrapWord
+	scroll := container.NewVScroll(text)
+	scroll.SetMinSize(fyne.NewSize(560, 320))
+
+	title := fmt.Sprintf("Pack contents (%d shared file(s), %d shared table(s))", len(report.Files), len(report.Tables))
+	if confirm == "" {
+		dialog.ShowCustom(title, "Close", scroll, parent)
+		return
+	}
+	content := container.NewBorder(widget.NewLabel("Some mods change the same game data. The last one loaded wins:"), nil, nil, nil, scroll)
+	dialog.ShowCustomConfirm(title, confirm, "Cancel", content, onDone, parent)
+}
+
+// formatPackConflicts liste les recouvrements entre mods, puis les fichiers du jeu
+// remplacés par un seul mod
+func formatPackConflicts(report *services.PackConflictReport) string {
+	sourceNames := func(sources []services.PackSource) string {
+		names := make([]string, len(sources))
+		for i, source := range sources {
+			names[i] = source.Pack
+			if source.ModID != "" {
+				names[i] = fmt.Sprintf("%s (%s)", source.Pack, source.Name)
+			}
+			if !source.Installed {
+				names[i] += " - planned"
+			}
+		}
+		return strings.Join(names, " → ")
+	}
+
+	lines := make([]string, 0)
+	if len(report.Tables) > 0 {
+		lines = append(lines, "Database tables changed by several mods:")
+		for _, table := range report.Tables {
+			lines = append(lines, fmt.Sprintf("  %s: %s", table.Table, sourceNames(table.Sources)))
+		}
+		lines = append(lines, "")
+	}
+	if conflicts := report.ModConflicts(); len(conflicts) > 0 {
+		lines = append(lines, "Files shipped by several mods:")
+		for _, conflict := range conflicts {
+			lines = append(lines, fmt.Sprintf("  %s: %s", conflict.Path, sourceNames(conflict.Sources)))
+		}
+		lines = append(lines, "")
+	}
+
+	overrides := make(map[string]int)
+	order := make([]string, 0)
+	for _, conflict := range report.Files {
+		if conflict.BetweenMods() || !conflict.OverridesVanilla() {
+			continue
+		}
+		for _, source := range conflict.Mods() {
+			if overrides[source.Pack] == 0 {
+				order = append(order, source.Pack)
+			}
+			overrides[source.Pack]++
+		}
+	}
+	if len(order) > 0 {
+		lines = append(lines, "Game files replaced by a single mod:")
+		for _, pack := range order {
+			lines = append(lines, fmt.Sprintf("  %s: %d file(s)", pack, overrides[pack]))
+		}
+		lines = append(lines, "")
+	}
+
+	if len(report.Warnings) > 0 {
+		lines = append(lines, "Unreadable packs (skipped):")
+		for _, warning := range report.Warnings {
+			lines = append(lines, "  "+warning)
+		}
+	}
+	if len(lines) == 0 {
+		return "No pack shares its contents with another mod."
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// showBackupSets liste les jeux de sauvegardes, du plus récent au plus ancien.
+// onRestore reçoit l'identifiant du jeu à restaurer, après confirmation.
+func showBackupSets(parent fyne.Window, sets []services.BackupIndex, onRestore func(id string)) {
+	if len(sets) == 0 {
+		dialog.ShowInformation("Backups", "No backup: no game file has been overwritten by a mod", parent)
+		return
+	}
+
+	var backups dialog.Dialog
+	rows := container.NewVBox()
+	for _, set := range sets {
+		label := widget.NewLabel(fmt.Sprintf("%s - %s - %d file(s)",
+			set.ModID, set.CreatedAt.Format("2006-01-02 15:04"), len(set.Entries)))
+		restore := widget.NewButton("Restore", func() {
+			files := make([]string, len(set.Entries))
+			for i, entry := range set.Entries {
+				files[i] = entry.Original
+			}
+			dialog.ShowConfirm("Restore backup",
+				fmt.Sprintf("Put back the files overwritten by %s?\n\n%s", set.ModID, strings.Join(files, "\n")),
+				func(confirmed bool) {
+					if confirmed {
+						backups.Hide()
+						onRestore(set.ID)
+					}
+				}, parent)
+		})
+		rows.Add(container.NewBorder(nil, nil, nil, restore, label))
+	}
+
+	scroll := container.NewVScroll(rows)
+	scroll.SetMinSize(fyne.NewSize(560, 320))
+	backups = dialog.NewCustom(fmt.Sprintf("Backups (%d)", len(sets)), "Close", scroll, parent)
+	backups.Show()
+}
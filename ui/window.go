@@ -2,11 +2,15 @@ package ui
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/container"
 	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
 	"fyne.io/fyne/v2/widget"
 
 	"mod-installer/config"
@@ -23,29 +27,46 @@ type MainWindow struct {
 	downloader     *services.DownloadService
 	installer      *services.InstallerService
 	vanillaService *services.VanillaService  // Service séparé pour vanilla
+	catalog        *services.CatalogService
+	loadOrder      *services.LoadOrderService
 	
 	gamePathEntry    *widget.Entry
 	scriptsPathEntry *widget.Entry
 	modList          *widget.List
 	progressBar      *widget.ProgressBar
 	statusLabel      *widget.Label
+	catalogLabel     *widget.Label
 	installBtn       *widget.Button
+	updateBtn        *widget.Button
+	uninstallBtn     *widget.Button
 	backupCheck      *widget.Check
 	
-	availableMods map[string]models.Mod
-	modKeys       []string
-	selectedMods  map[string]bool
+	availableMods  map[string]models.Mod
+	catalogState   services.CatalogSnapshot
+	modGroups      []models.ModGroup // Une ligne par mod, toutes versions confondues
+	selectedMods   map[string]bool   // Par clé de mod (ModGroup.Key)
+	chosenVersions map[string]string // Version choisie par clé de mod, la plus récente sinon
+	updates        map[string]services.ModUpdate // Mises à jour des mods installés, par clé de mod
+	installed      map[string]*models.Mod        // Version installée (manifeste présent), par clé de mod
+	installStatus  map[string]error              // Installations présentes sur le disque, par ID : nil si intacte
+	installStates map[string]models.Installation // Avancement par mod pendant une installation
 }
 
 func NewMainWindow(app fyne.App, cfg *config.Config) *MainWindow {
 	window := app.NewWindow("Mod Installer")
 	window.Resize(fyne.NewSize(float32(cfg.WindowWidth), float32(cfg.WindowHeight)))
 	
-	fmt.Println("Loading mods...")
-	availableMods, err := api.FetchAllModMeta()
-	if err != nil {
-		fmt.Printf("API Error: %v\n", err)
-		availableMods = getExampleModsMap()
+	// Sources de catalogue configurées (dépôt public, catalogues privés, dossiers locaux)
+	if err := api.ConfigureCatalog(cfg); err != nil {
+		fmt.Printf("Catalog sources error: %v\n", err)
+	}
+	
+	// Le dernier catalogue enregistré s'affiche tout de suite, le réseau est interrogé ensuite
+	catalog := services.NewCatalogService(cfg)
+	catalogState := catalog.LoadCached()
+	availableMods := catalogState.Mods
+	if availableMods == nil {
+		availableMods = make(map[string]models.Mod)
 	}
 	
 	mw := &MainWindow{
@@ -55,12 +76,31 @@ func NewMainWindow(app fyne.App, cfg *config.Config) *MainWindow {
 		downloader:     services.NewDownloadService(cfg.TempPath, cfg.VerifyChecksums),
 		installer:      services.NewInstallerService(cfg),
 		vanillaService: services.NewVanillaService(cfg.GamePath, cfg.ScriptsPath, cfg.TempPath),
+		catalog:        catalog,
+		loadOrder:      services.NewLoadOrderService(cfg),
 		availableMods:  availableMods,
+		catalogState:   catalogState,
 		selectedMods:   make(map[string]bool),
+		chosenVersions: make(map[string]string),
+		updates:        make(map[string]services.ModUpdate),
+		installed:      make(map[string]*models.Mod),
+		installStatus:  make(map[string]error),
+		installStates:  make(map[string]models.Installation),
 	}
 	
+	// Terminer ou annuler une installation interrompue au lancement précédent
+	if recovered, err := mw.installer.RecoverInterruptedInstalls(); err != nil {
+		fmt.Printf("Recovery error: %v\n", err)
+	} else if len(recovered) > 0 {
+		fmt.Printf("Recovered interrupted installs: %v\n", recovered)
+	}
+	mw.installer.Cleanup()
+	
 	mw.loadAllMods()
 	mw.setupUI()
+	
+	fmt.Println("Loading mods...")
+	go mw.refreshCatalog(false)
 	return mw
 }
 
@@ -74,11 +114,106 @@ func (mw *MainWindow) loadAllMods() {
 		}
 	}
 	
-	// Créer la liste des clés
-	mw.modKeys = make([]string, 0, len(mw.availableMods))
-	for key := range mw.availableMods {
-		mw.modKeys = append(mw.modKeys, key)
+	// Regrouper les versions de chaque mod, dans l'ordre de chargement ; les fichiers
+	// vanilla restent en tête
+	catalog := models.GroupMods(mw.availableMods)
+	groups := catalog.Groups()
+	mw.modGroups = make([]models.ModGroup, 0, len(groups))
+	ordered := make([]models.ModGroup, 0, len(groups))
+	for _, group := range groups {
+		if group.Key() == "vanilla_pack" {
+			mw.modGroups = append(mw.modGroups, group)
+		} else {
+			ordered = append(ordered, group)
+		}
+	}
+	mw.loadOrder.SortGroups(ordered)
+	mw.modGroups = append(mw.modGroups, ordered...)
+	
+	// Mises à jour des mods installés dans ce jeu
+	mw.updates = make(map[string]services.ModUpdate)
+	updates, err := mw.installer.AvailableUpdates(catalog)
+	if err != nil {
+		fmt.Printf("Update check error: %v\n", err)
+	}
+	for _, update := range updates {
+		mw.updates[update.Latest.GroupKey()] = update
+	}
+	
+	mw.loadInstalledMods()
+}
+
+// loadInstalledMods relit les manifestes et vérifie une fois les fichiers installés,
+// pour que la liste n'ait pas à le faire à chaque affichage de ligne
+func (mw *MainWindow) loadInstalledMods() {
+	mw.installed = make(map[string]*models.Mod)
+	mw.installStatus = make(map[string]error)
+	
+	manifests, err := mw.installer.InstalledMods()
+	if err != nil {
+		fmt.Printf("Installed mods error: %v\n", err)
+	}
+	recorded := make(map[string]bool, len(manifests))
+	for _, manifest := range manifests {
+		recorded[manifest.ModID] = true
+	}
+	
+	for _, group := range mw.modGroups {
+		for i := range group.Versions {
+			mod := &group.Versions[i]
+			if !recorded[mod.ID] {
+				continue
+			}
+			mw.installed[group.Key()] = mod
+			if installed, err := mw.installer.GetInstallationStatus(mod); installed || err != nil {
+				mw.installStatus[mod.ID] = err
+			}
+			break
+		}
+	}
+}
+
+// moveMod déplace un mod dans l'ordre de chargement
+func (mw *MainWindow) moveMod(modKey string, delta int) {
+	keys := make([]string, 0, len(mw.modGroups))
+	for _, group := range mw.modGroups {
+		if group.Key() != "vanilla_pack" {
+			keys = append(keys, group.Key())
+		}
+	}
+	if err := mw.loadOrder.Move(keys, modKey, delta); err != nil {
+		dialog.ShowError(err, mw.window)
+		return
+	}
+	mw.loadAllMods()
+	mw.modList.Refresh()
+}
+
+// chosenMod retourne la version retenue pour un mod : celle choisie dans la liste si
+// elle existe encore, sinon la plus récente
+func (mw *MainWindow) chosenMod(group models.ModGroup) *models.Mod {
+	if version, ok := mw.chosenVersions[group.Key()]; ok {
+		if mod := group.Versions.Find(version); mod != nil {
+			return mod
+		}
 	}
+	return group.Versions.Latest()
+}
+
+// installedMod retourne la version installée d'un mod, s'il y en a une
+func (mw *MainWindow) installedMod(group models.ModGroup) *models.Mod {
+	return mw.installed[group.Key()]
+}
+
+// selectedGroups retourne les mods cochés, dans l'ordre de la liste
+func (mw *MainWindow) selectedGroups() []models.ModGroup {
+	groups := make([]models.ModGroup, 0)
+	for _, group := range mw.modGroups {
+		if mw.selectedMods[group.Key()] {
+			groups = append(groups, group)
+		}
+	}
+	return groups
 }
 
 func (mw *MainWindow) setupUI() {
@@ -133,41 +268,79 @@ func (mw *MainWindow) setupUI() {
 		}, mw.window)
 	})
 	
-	mw.backupCheck = widget.NewCheck("Create backups", func(checked bool) {
+	mw.backupCheck = widget.NewCheck("Create backups", nil)
+	mw.backupCheck.SetChecked(mw.config.CreateBackups)
+	mw.backupCheck.OnChanged = func(checked bool) {
+		mw.config.CreateBackups = checked
+		if err := mw.config.Save(); err != nil {
+			dialog.ShowError(fmt.Errorf("erreur enregistrement de la configuration: %w", err), mw.window)
+		}
 		mw.installer = services.NewInstallerService(mw.config)
-	})
-	mw.backupCheck.SetChecked(false)
+	}
 	
 	mw.modList = widget.NewList(
-		func() int { return len(mw.modKeys) },
+		func() int { return len(mw.modGroups) },
 		func() fyne.CanvasObject {
 			check := widget.NewCheck("", nil)
 			nameLabel := widget.NewLabel("Name")
+			versionSelect := widget.NewSelect(nil, nil)
+			upBtn := widget.NewButton("▲", nil)
+			downBtn := widget.NewButton("▼", nil)
 			descLabel := widget.NewLabel("Description")
 			sizeLabel := widget.NewLabel("Size")
 			statusLabel := widget.NewLabel("")
 			
 			return container.NewVBox(
-				container.NewHBox(check, nameLabel, widget.NewSeparator(), sizeLabel),
+				container.NewHBox(check, nameLabel, versionSelect, widget.NewSeparator(), sizeLabel, layout.NewSpacer(), upBtn, downBtn),
 				descLabel, statusLabel,
 			)
 		},
 		func(id widget.ListItemID, item fyne.CanvasObject) {
-			if id >= len(mw.modKeys) { return }
+			if id >= len(mw.modGroups) { return }
 			
-			modKey := mw.modKeys[id]
-			mod, exists := mw.availableMods[modKey]
-			if !exists { return }
+			group := mw.modGroups[id]
+			modKey := group.Key()
+			chosen := mw.chosenMod(group)
+			if chosen == nil { return }
+			mod := *chosen
 			
 			vbox := item.(*fyne.Container)
 			topRow := vbox.Objects[0].(*fyne.Container)
 			check := topRow.Objects[0].(*widget.Check)
 			nameLabel := topRow.Objects[1].(*widget.Label)
-			sizeLabel := topRow.Objects[3].(*widget.Label)
+			versionSelect := topRow.Objects[2].(*widget.Select)
+			sizeLabel := topRow.Objects[4].(*widget.Label)
+			upBtn := topRow.Objects[6].(*widget.Button)
+			downBtn := topRow.Objects[7].(*widget.Button)
 			descLabel := vbox.Objects[1].(*widget.Label)
 			statusLabel := vbox.Objects[2].(*widget.Label)
 			
-			nameLabel.SetText(fmt.Sprintf("%s v%s", mod.Name, mod.Version))
+			// Ordre de chargement : un mod plus bas est installé plus tard et l'emporte
+			if modKey == "vanilla_pack" {
+				upBtn.Hide()
+				downBtn.Hide()
+			} else {
+				upBtn.OnTapped = func() { mw.moveMod(modKey, -1) }
+				downBtn.OnTapped = func() { mw.moveMod(modKey, 1) }
+				upBtn.Show()
+				downBtn.Show()
+			}
+			
+			// Plusieurs versions : choix dans la liste, la plus récente par défaut
+			versionSelect.OnChanged = nil
+			if len(group.Versions) > 1 {
+				nameLabel.SetText(mod.Name)
+				versionSelect.Options = group.Versions.Versions()
+				versionSelect.SetSelected(mod.Version)
+				versionSelect.OnChanged = func(version string) {
+					mw.chosenVersions[modKey] = version
+					mw.modList.RefreshItem(id)
+				}
+				versionSelect.Show()
+			} else {
+				nameLabel.SetText(fmt.Sprintf("%s v%s", mod.Name, mod.Version))
+				versionSelect.Hide()
+			}
       if mod.Version == "original" {
         nameLabel.SetText(fmt.Sprintf("%s", mod.Name))
       }
@@ -191,9 +364,25 @@ func (mw *MainWindow) setupUI() {
 				}
 			}
 			
-			if installed, _ := mw.installer.GetInstallationStatus(&mod); installed {
+			if other := mw.installedMod(group); other != nil {
+				if err, present := mw.installStatus[other.ID]; err != nil {
+					if statusText != "" { statusText += " | " }
+					statusText += "⚠️ " + err.Error()
+				} else if present && other.ID == mod.ID {
+					if statusText != "" { statusText += " | " }
+					statusText += "✅ Installed"
+				} else if present {
+					if statusText != "" { statusText += " | " }
+					statusText += fmt.Sprintf("✅ Installed v%s", other.Version)
+				}
+			}
+			if update, ok := mw.updates[modKey]; ok {
+				if statusText != "" { statusText += " | " }
+				statusText += fmt.Sprintf("⬆️ v%s available", update.Latest.Version)
+			}
+			if state, running := mw.installStates[mod.ID]; running {
 				if statusText != "" { statusText += " | " }
-				statusText += "✅ Installed"
+				statusText += formatInstallState(state)
 			}
 			statusLabel.SetText(statusText)
 			
@@ -212,10 +401,15 @@ func (mw *MainWindow) setupUI() {
 	mw.progressBar = widget.NewProgressBar()
 	mw.progressBar.Hide()
 	mw.statusLabel = widget.NewLabel("Ready")
+	mw.catalogLabel = widget.NewLabel(formatCatalogState(mw.catalogState, mw.config.LocalCatalogPath))
 	
 	mw.installBtn = widget.NewButton("Install selected", mw.installSelectedMods)
+	mw.updateBtn = widget.NewButton("Update", mw.updateMods)
+	mw.uninstallBtn = widget.NewButton("Uninstall selected", mw.uninstallSelectedMods)
 	refreshBtn := widget.NewButton("Refresh", mw.refreshModList)
 	cacheBtn := widget.NewButton("Cache", mw.showCacheManager)
+	backupsBtn := widget.NewButton("Backups", mw.showBackups)
+	packsBtn := widget.NewButton("Check packs", mw.checkPacks)
 	
 	topSection := container.NewVBox(
 		title,
@@ -224,16 +418,18 @@ func (mw *MainWindow) setupUI() {
 		container.NewBorder(nil, nil, nil, browseGameBtn, mw.gamePathEntry),
 		widget.NewLabel("Scripts path:"),
 		container.NewBorder(nil, nil, nil, browseScriptsBtn, mw.scriptsPathEntry),
+		mw.backupCheck,
 	)
 	
 	bottomSection := container.NewVBox(
 		mw.progressBar,
 		mw.statusLabel,
-		container.NewHBox(mw.installBtn, refreshBtn, cacheBtn),
+		container.NewHBox(mw.installBtn, mw.updateBtn, mw.uninstallBtn, refreshBtn, packsBtn, backupsBtn, cacheBtn),
 	)
 	
 	modListContainer := container.NewBorder(
-		widget.NewLabel("Available mods:"), nil, nil, nil, mw.modList,
+		container.NewBorder(nil, nil, widget.NewLabel("Available mods:"), mw.catalogLabel),
+		nil, nil, nil, mw.modList,
 	)
 	
 	lowerSection := container.NewVSplit(modListContainer, bottomSection)
@@ -262,6 +458,19 @@ func (mw *MainWindow) toggleModSelection(modKey string, selected bool) {
 	}
 }
 
+// keepSelection retire de la sélection les mods absents du nouveau catalogue
+func (mw *MainWindow) keepSelection() {
+	present := make(map[string]bool, len(mw.modGroups))
+	for _, group := range mw.modGroups {
+		present[group.Key()] = true
+	}
+	for modKey, selected := range mw.selectedMods {
+		if !selected || !present[modKey] {
+			delete(mw.selectedMods, modKey)
+		}
+	}
+}
+
 func (mw *MainWindow) updateGamePathValidation() {
 	if mw.installer.IsGamePathValid() {
 		mw.statusLabel.SetText("Valid path")
@@ -272,14 +481,14 @@ func (mw *MainWindow) updateGamePathValidation() {
 }
 
 func (mw *MainWindow) installSelectedMods() {
-	selectedModKeys := make([]string, 0)
-	for key, selected := range mw.selectedMods {
-		if selected {
-			selectedModKeys = append(selectedModKeys, key)
+	selected := make([]models.Mod, 0)
+	for _, group := range mw.selectedGroups() {
+		if mod := mw.chosenMod(group); mod != nil {
+			selected = append(selected, *mod)
 		}
 	}
 	
-	if len(selectedModKeys) == 0 {
+	if len(selected) == 0 {
 		dialog.ShowInformation("No selection", "Select at least one mod", mw.window)
 		return
 	}
@@ -293,104 +502,180 @@ func (mw *MainWindow) installSelectedMods() {
 		return
 	}
 	
-	fyne.Do(func() {
+	// Les dépendances sont ajoutées et chaque mod est installé après les siennes ;
+	// les fichiers vanilla, restaurés en premier, n'entrent pas dans le plan
+	vanilla := make([]models.Mod, 0)
+	mods := make([]models.Mod, 0, len(selected))
+	for _, mod := range selected {
+		if mod.ID == "vanilla_pack" {
+			vanilla = append(vanilla, mod)
+		} else {
+			mods = append(mods, mod)
+		}
+	}
+	mw.loadOrder.SortMods(mods)
+	plan, err := mw.installer.PlanInstall(models.GroupMods(mw.availableMods), mods)
+	if err != nil {
+		dialog.ShowError(err, mw.window)
+		return
+	}
+	
+	dialog.ShowConfirm("Install plan", formatInstallPlan(vanilla, plan), func(confirmed bool) {
+		if !confirmed {
+			return
+		}
+		mw.installStates = make(map[string]models.Installation)
 		mw.statusLabel.SetText("Preparing...")
 		mw.progressBar.Show()
 		mw.progressBar.SetValue(0)
 		mw.installBtn.Disable()
-	})
-	
-	go mw.performInstallation(selectedModKeys)
+		
+		go mw.performInstallation(append(vanilla, plan.Mods()...))
+	}, mw.window)
 }
 
-func (mw *MainWindow) performInstallation(modKeys []string) {
+func (mw *MainWindow) performInstallation(selected []models.Mod) {
+	handedOff := false // La suite de l'installation attend le choix de l'utilisateur
 	defer func() {
-		fyne.Do(func() {
-			mw.progressBar.Hide()
-			mw.installBtn.Enable()
-		})
+		if !handedOff {
+			fyne.Do(mw.endInstallation)
+		}
 	}()
 	
-	totalMods := len(modKeys)
 	ctx := context.Background()
 	
-	for i, modKey := range modKeys {
-		mod, exists := mw.availableMods[modKey]
-		if !exists { continue }
-		
-		// Traitement différent pour les mods vanilla
+	// Les fichiers vanilla ne sont restaurés qu'une fois les conflits acceptés
+	vanilla := make([]models.Mod, 0)
+	mods := make([]models.Mod, 0, len(selected))
+	for _, mod := range selected {
 		if mod.ID == "vanilla_pack" {
-			fyne.Do(func() {
-				mw.statusLabel.SetText(fmt.Sprintf("Restoring %s (%d/%d)", mod.Name, i+1, totalMods))
-			})
-			
-			// Utiliser VanillaService pour restaurer
-			err := mw.vanillaService.RestoreVanillaFile(&mod)
-			if err != nil {
-				fyne.Do(func() {
-					mw.statusLabel.SetText(fmt.Sprintf("Restore error %s", mod.Name))
-					dialog.ShowError(err, mw.window)
-				})
-				return
-			}
-			
-			overallProgress := float64(i+1) / float64(totalMods)
-			fyne.Do(func() { mw.progressBar.SetValue(overallProgress) })
-			continue
+			vanilla = append(vanilla, mod)
+		} else {
+			mods = append(mods, mod)
 		}
-		
-		// Traitement normal pour les autres mods
+	}
+	
+	// Toutes les archives sont téléchargées, puis leurs fichiers comparés entre eux et
+	// avec les mods installés avant d'écrire quoi que ce soit dans le jeu
+	queue := services.NewInstallQueue(mw.downloader, mw.installer, mw.config.MaxConcurrentDownloads)
+	progress := mw.trackProgress(len(mods))
+	archives, err := queue.Download(ctx, mods, progress)
+	var conflicts []services.FileConflict
+	var packReport *services.PackConflictReport
+	if err == nil {
+		conflicts, err = mw.installer.CheckFileConflicts(mods, archives)
+	}
+	if err == nil {
+		packReport, err = mw.installer.CheckPackConflicts(mods, archives)
+	}
+	if err == nil && (len(conflicts) > 0 || packReport.HasModConflicts()) {
+		handedOff = true
 		fyne.Do(func() {
-			mw.statusLabel.SetText(fmt.Sprintf("Downloading %s (%d/%d)", mod.Name, i+1, totalMods))
+			mw.reviewConflicts(conflicts, packReport, func(winners map[string]string) {
+				if winners == nil {
+					mw.statusLabel.SetText("Installation cancelled")
+					mw.endInstallation()
+					return
+				}
+				queue.SetFileWinners(winners)
+				go func() {
+					defer fyne.Do(mw.endInstallation)
+					mw.runInstallQueue(ctx, queue, vanilla, mods, archives, progress, len(selected))
+				}()
+			})
 		})
-		
-		downloadProgressCallback := func(downloaded, total int64) {
-			if total > 0 {
-				modProgress := float64(downloaded) / float64(total)
-				overallProgress := (float64(i)*2 + modProgress) / float64(totalMods*2)
-				fyne.Do(func() { mw.progressBar.SetValue(overallProgress) })
-			}
+		return
+	}
+	if err != nil {
+		fyne.Do(func() {
+			mw.statusLabel.SetText("Installation error")
+			dialog.ShowError(err, mw.window)
+		})
+		return
+	}
+	mw.runInstallQueue(ctx, queue, vanilla, mods, archives, progress, len(selected))
+}
+
+// reviewConflicts montre d'abord les packs dont le contenu se recouvre, puis demande
+// le propriétaire de chaque fichier en conflit. onDone reçoit nil si l'utilisateur
+// annule.
+func (mw *MainWindow) reviewConflicts(conflicts []services.FileConflict, packReport *services.PackConflictReport, onDone func(winners map[string]string)) {
+	chooseFiles := func() {
+		if len(conflicts) == 0 {
+			onDone(map[string]string{})
+			return
 		}
-		
-		filePath, err := mw.downloader.DownloadMod(ctx, &mod, downloadProgressCallback)
-		if err != nil {
-			fyne.Do(func() {
-				mw.statusLabel.SetText(fmt.Sprintf("Download error %s", mod.Name))
-				dialog.ShowError(err, mw.window)
-			})
+		mw.statusLabel.SetText(fmt.Sprintf("%d file conflict(s)", len(conflicts)))
+		showFileConflicts(mw.window, conflicts, onDone)
+	}
+	if !packReport.HasModConflicts() {
+		chooseFiles()
+		return
+	}
+
+	mw.statusLabel.SetText("Mods share pack contents")
+	showPackConflicts(mw.window, packReport, "Continue", func(confirmed bool) {
+		if !confirmed {
+			onDone(nil)
 			return
 		}
-		
+		chooseFiles()
+	})
+}
+
+// checkPacks analyse le contenu des packs installés dans data/
+func (mw *MainWindow) checkPacks() {
+	mw.statusLabel.SetText("Reading pack contents...")
+	go func() {
+		report, err := mw.installer.CheckPackConflicts(nil, nil)
 		fyne.Do(func() {
-			mw.statusLabel.SetText(fmt.Sprintf("Installing %s (%d/%d)", mod.Name, i+1, totalMods))
+			if err != nil {
+				mw.statusLabel.SetText("Pack analysis error")
+				dialog.ShowError(err, mw.window)
+				return
+			}
+			mw.statusLabel.SetText("Ready")
+			showPackConflicts(mw.window, report, "", nil)
+		})
+	}()
+}
+
+func (mw *MainWindow) endInstallation() {
+	mw.progressBar.Hide()
+	mw.installBtn.Enable()
+}
+
+// runInstallQueue restaure les fichiers vanilla puis installe les mods téléchargés
+// (archives, par ID de mod), dans l'ordre du plan
+func (mw *MainWindow) runInstallQueue(ctx context.Context, queue *services.InstallQueue, vanilla, mods []models.Mod, archives map[string]string, progress services.InstallEventCallback, totalMods int) {
+	for _, mod := range vanilla {
+		fyne.Do(func() {
+			mw.statusLabel.SetText(fmt.Sprintf("Restoring %s", mod.Name))
 		})
 		
-		installProgressCallback := func(currentFile string, processed, total int) {
-			installProgress := float64(processed) / float64(total)
-			overallProgress := (float64(i)*2 + 1 + installProgress) / float64(totalMods*2)
-			fyne.Do(func() {
-				mw.progressBar.SetValue(overallProgress)
-				if currentFile != "" {
-					mw.statusLabel.SetText(fmt.Sprintf("Installing %s: %s", mod.Name, currentFile))
-				}
-			})
-		}
-		
-		err = mw.installer.InstallMod(ctx, &mod, filePath, installProgressCallback)
-		if err != nil {
+		// Utiliser VanillaService pour restaurer
+		if err := mw.vanillaService.RestoreVanillaFile(&mod); err != nil {
 			fyne.Do(func() {
-				mw.statusLabel.SetText(fmt.Sprintf("Installation error %s", mod.Name))
+				mw.statusLabel.SetText(fmt.Sprintf("Restore error %s", mod.Name))
 				dialog.ShowError(err, mw.window)
 			})
 			return
 		}
-		
-		overallProgress := float64(i+1) / float64(totalMods)
-		fyne.Do(func() { mw.progressBar.SetValue(overallProgress) })
+	}
+	
+	// Archives déjà téléchargées, installations séquentielles
+	if err := queue.RunDownloaded(ctx, mods, archives, progress); err != nil {
+		fyne.Do(func() {
+			mw.statusLabel.SetText("Installation error")
+			dialog.ShowError(err, mw.window)
+		})
+		return
 	}
 	
 	fyne.Do(func() {
+		mw.installStates = make(map[string]models.Installation)
 		mw.statusLabel.SetText(fmt.Sprintf("Completed (%d mods)", totalMods))
+		mw.loadAllMods()
 		mw.refreshModList()
 		
 		_, cacheSize, cacheCount, _ := mw.downloader.GetCacheInfo()
@@ -402,19 +687,196 @@ func (mw *MainWindow) performInstallation(modKeys []string) {
 	})
 }
 
-func (mw *MainWindow) refreshModList() {
-	availableMods, err := api.FetchAllModMeta()
+// trackProgress retourne le suivi d'une file de total mods : chaque mod compte pour
+// moitié en téléchargement, moitié en installation
+func (mw *MainWindow) trackProgress(total int) services.InstallEventCallback {
+	progress := make(map[string]float64, total)
+	return func(state models.Installation, detail string) {
+		fyne.Do(func() {
+			mw.installStates[state.ModID] = state
+			
+			// Chaque mod compte pour moitié en téléchargement, moitié en installation
+			switch state.Status {
+			case models.StatusDownloading:
+				progress[state.ModID] = state.Progress / 2
+			case models.StatusInstalling:
+				progress[state.ModID] = 0.5 + state.Progress/2
+				if detail != "" {
+					mw.statusLabel.SetText(fmt.Sprintf("Installing %s", detail))
+				}
+			case models.StatusCompleted:
+				progress[state.ModID] = 1
+			}
+			
+			overall := 0.0
+			for _, p := range progress {
+				overall += p
+			}
+			if total > 0 {
+				mw.progressBar.SetValue(overall / float64(total))
+			}
+			mw.modList.Refresh()
+		})
+	}
+}
+
+// updateMods met à jour les mods cochés, ou tous les mods installés si aucun mod
+// coché n'a de mise à jour
+func (mw *MainWindow) updateMods() {
+	updates := make([]services.ModUpdate, 0)
+	for _, group := range mw.selectedGroups() {
+		if update, ok := mw.updates[group.Key()]; ok {
+			updates = append(updates, update)
+		}
+	}
+	if len(updates) == 0 {
+		for _, group := range mw.modGroups {
+			if update, ok := mw.updates[group.Key()]; ok {
+				updates = append(updates, update)
+			}
+		}
+	}
+	
+	if len(updates) == 0 {
+		dialog.ShowInformation("Updates", "All installed mods are up to date", mw.window)
+		return
+	}
+	if !mw.installer.IsGamePathValid() || !mw.installer.IsScriptsPathValid() {
+		dialog.ShowError(fmt.Errorf("invalid game or scripts path"), mw.window)
+		return
+	}
+	
+	lines := make([]string, len(updates))
+	for i, update := range updates {
+		lines[i] = fmt.Sprintf("%s: v%s → v%s", update.Latest.Name, update.Installed.Version, update.Latest.Version)
+	}
+	dialog.ShowConfirm("Update mods", strings.Join(lines, "\n")+"\n\nThe installed versions will be replaced.",
+		func(confirmed bool) {
+			if !confirmed {
+				return
+			}
+			mw.installStates = make(map[string]models.Installation)
+			mw.statusLabel.SetText("Preparing update...")
+			mw.progressBar.Show()
+			mw.progressBar.SetValue(0)
+			mw.installBtn.Disable()
+			mw.updateBtn.Disable()
+			go mw.performUpdate(updates)
+		}, mw.window)
+}
+
+func (mw *MainWindow) performUpdate(updates []services.ModUpdate) {
+	defer func() {
+		fyne.Do(func() {
+			mw.progressBar.Hide()
+			mw.installBtn.Enable()
+			mw.updateBtn.Enable()
+		})
+	}()
+	
+	queue := services.NewInstallQueue(mw.downloader, mw.installer, mw.config.MaxConcurrentDownloads)
+	err := queue.Update(context.Background(), updates, mw.trackProgress(len(updates)))
+	
+	fyne.Do(func() {
+		mw.installStates = make(map[string]models.Installation)
+		mw.loadAllMods()
+		mw.modList.Refresh()
+		if err != nil {
+			mw.statusLabel.SetText("Update error")
+			dialog.ShowError(err, mw.window)
+			return
+		}
+		mw.statusLabel.SetText(fmt.Sprintf("Updated %d mod(s)", len(updates)))
+	})
+}
+
+func (mw *MainWindow) uninstallSelectedMods() {
+	// La version désinstallée est celle qui est installée, pas celle choisie dans la liste
+	mods := make([]models.Mod, 0)
+	for _, group := range mw.selectedGroups() {
+		if mod := mw.installedMod(group); mod != nil && mod.ID != "vanilla_pack" {
+			mods = append(mods, *mod)
+		}
+	}
+	
+	if len(mods) == 0 {
+		dialog.ShowInformation("No selection", "Select at least one installed mod", mw.window)
+		return
+	}
+	
+	for _, mod := range mods {
+		mw.uninstallMod(mod, false)
+	}
+}
+
+func (mw *MainWindow) uninstallMod(mod models.Mod, force bool) {
+	report, err := mw.installer.UninstallMod(&mod, force)
+	
+	var sharedErr *services.SharedFilesError
+	if errors.As(err, &sharedErr) {
+		dialog.ShowConfirm("Shared files",
+			sharedErr.Error()+"\n\nUninstall anyway and keep these files?",
+			func(confirmed bool) {
+				if confirmed {
+					mw.uninstallMod(mod, true)
+				}
+			}, mw.window)
+		return
+	}
 	if err != nil {
 		dialog.ShowError(err, mw.window)
 		return
 	}
 	
-	mw.availableMods = availableMods
-	mw.loadAllMods() // Recharger tous les mods incluant vanilla
-	
-	mw.selectedMods = make(map[string]bool)
+	message := fmt.Sprintf("%s uninstalled\nRemoved: %d\nRestored: %d\nKept: %d",
+		mod.Name, len(report.Removed), len(report.Restored), len(report.Kept))
+	if len(report.Warnings) > 0 {
+		message += "\n\n" + strings.Join(report.Warnings, "\n")
+	}
+	mw.statusLabel.SetText(fmt.Sprintf("Uninstalled %s", mod.Name))
+	mw.loadAllMods()
 	mw.modList.Refresh()
-	mw.statusLabel.SetText("Ready")
+	dialog.ShowInformation("Uninstalled", message, mw.window)
+}
+
+func (mw *MainWindow) refreshModList() {
+	mw.statusLabel.SetText("Refreshing catalog...")
+	go mw.refreshCatalog(true)
+}
+
+// refreshCatalog interroge le réseau en arrière-plan. En cas d'échec, les mods déjà
+// affichés sont conservés et l'étiquette du catalogue indique leur ancienneté.
+func (mw *MainWindow) refreshCatalog(manual bool) {
+	snapshot := mw.catalog.Refresh()
+	if snapshot.Err != nil {
+		fmt.Printf("API Error: %v\n", snapshot.Err)
+	}
+	
+	fyne.Do(func() {
+		if snapshot.Status == services.CatalogLive || mw.catalogState.Status == services.CatalogNone {
+			mw.availableMods = snapshot.Mods
+			if mw.availableMods == nil {
+				mw.availableMods = make(map[string]models.Mod)
+			}
+			mw.loadAllMods() // Recharger tous les mods incluant vanilla
+			mw.keepSelection()
+			mw.modList.Refresh()
+		}
+		mw.catalogState = snapshot
+		mw.catalogLabel.SetText(formatCatalogState(snapshot, mw.config.LocalCatalogPath))
+		
+		if snapshot.Status == services.CatalogLive && !snapshot.Changes.Empty() {
+			dialog.ShowInformation("Catalog changes", formatCatalogChanges(snapshot.Changes, len(mw.updates)), mw.window)
+		}
+		
+		switch {
+		case snapshot.Err != nil && manual:
+			mw.statusLabel.SetText("Catalog refresh failed")
+			dialog.ShowError(snapshot.Err, mw.window)
+		case manual:
+			mw.statusLabel.SetText("Ready")
+		}
+	})
 }
 
 func (mw *MainWindow) showCacheManager() {
@@ -440,6 +902,102 @@ func (mw *MainWindow) showCacheManager() {
 		}, mw.window)
 }
 
+// showBackups propose de restaurer les fichiers du jeu écrasés par une installation
+func (mw *MainWindow) showBackups() {
+	sets, err := mw.installer.ListBackupSets()
+	if err != nil {
+		dialog.ShowError(err, mw.window)
+		return
+	}
+	showBackupSets(mw.window, sets, func(id string) {
+		restored, err := mw.installer.RestoreBackupSet(id)
+		mw.loadAllMods()
+		mw.modList.Refresh()
+		if err != nil {
+			mw.statusLabel.SetText("Restore error")
+			dialog.ShowError(err, mw.window)
+			return
+		}
+		mw.statusLabel.SetText(fmt.Sprintf("Restored %d file(s)", len(restored)))
+	})
+}
+
+func formatInstallState(state models.Installation) string {
+	switch state.Status {
+	case models.StatusDownloading, models.StatusInstalling:
+		return fmt.Sprintf("⏳ %s %.0f%%", state.Status, state.Progress*100)
+	case models.StatusFailed:
+		return fmt.Sprintf("❌ %s: %s", state.Status, state.Error)
+	default:
+		return fmt.Sprintf("⏳ %s", state.Status)
+	}
+}
+
+// formatCatalogState décrit l'origine des mods affichés
+func formatCatalogState(state services.CatalogSnapshot, localPath string) string {
+	switch state.Status {
+	case services.CatalogLive:
+		if localPath != "" {
+			return fmt.Sprintf("📁 Local catalog (%s)", localPath)
+		}
+		return "🟢 Live catalog"
+	case services.CatalogCached:
+		return fmt.Sprintf("🟠 Cached catalog (%s old)", formatAge(state.Age()))
+	default:
+		if state.Err != nil {
+			return "🔴 No catalog (offline)"
+		}
+		return "⏳ Loading catalog..."
+	}
+}
+
+// formatInstallPlan liste les mods dans l'ordre d'installation
+func formatInstallPlan(vanilla []models.Mod, plan *services.InstallPlan) string {
+	lines := make([]string, 0, len(vanilla)+len(plan.Steps))
+	for _, mod := range vanilla {
+		lines = append(lines, fmt.Sprintf("%d. Restore %s", len(lines)+1, mod.Name))
+	}
+	for _, step := range plan.Steps {
+		line := fmt.Sprintf("%d. %s v%s", len(lines)+1, step.Mod.Name, step.Mod.Version)
+		if step.Dependency {
+			line += fmt.Sprintf(" (required by %s)", strings.Join(step.RequiredBy, ", "))
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// formatCatalogChanges résume ce qui a changé depuis le dernier rafraîchissement
+func formatCatalogChanges(changes services.CatalogChanges, updates int) string {
+	lines := make([]string, 0)
+	for _, mod := range changes.NewMods {
+		lines = append(lines, fmt.Sprintf("🆕 %s v%s", mod.Name, mod.Version))
+	}
+	for _, mod := range changes.NewVersions {
+		lines = append(lines, fmt.Sprintf("⬆️ %s v%s", mod.Name, mod.Version))
+	}
+	for _, mod := range changes.Removed {
+		lines = append(lines, fmt.Sprintf("➖ %s v%s", mod.Name, mod.Version))
+	}
+	if updates > 0 {
+		lines = append(lines, "", fmt.Sprintf("%d installed mod(s) can be updated", updates))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func formatAge(age time.Duration) string {
+	switch {
+	case age < time.Minute:
+		return "less than a minute"
+	case age < time.Hour:
+		return fmt.Sprintf("%d min", int(age.Minutes()))
+	case age < 48*time.Hour:
+		return fmt.Sprintf("%d h", int(age.Hours()))
+	default:
+		return fmt.Sprintf("%d days", int(age.Hours()/24))
+	}
+}
+
 func formatFileSize(bytes int64) string {
 	const unit = 1024
 	if bytes < unit {
@@ -452,18 +1010,3 @@ func formatFileSize(bytes int64) string {
 	}
 	return fmt.Sprintf("%.1f %cB", float64(bytes)/float64(div), "KMGTPE"[exp])
 }
-
-func getExampleModsMap() map[string]models.Mod {
-	return map[string]models.Mod{
-		"mod1": {
-			ID: "mod1", Name: "UI Mod", Version: "1.2.3",
-			Description: "Improves interface", FileSize: 2048576,
-			DownloadURL: "https://example.com/mod1.zip",
-		},
-		"mod2": {
-			ID: "mod2", Name: "Extra Content", Version: "2.0.1", 
-			Description: "Content mod", FileSize: 10485760,
-			DownloadURL: "https://example.com/mod2.zip",
-		},
-	}
-}
@@ -1 +1,68 @@
-package utils;
+package utils
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"mod-installer/utils/ntw"
+)
+
+// ExtractedFile décrit un fichier écrit sur le disque par un extracteur d'archive
+type ExtractedFile struct {
+	Name     string // Nom de l'entrée dans l'archive
+	DestRoot string // Dossier racine de destination (data ou scripts)
+	Path     string // Chemin absolu du fichier écrit
+	Replaced bool   // Un fichier existait déjà à cet emplacement
+}
+
+// Extractor est la signature commune des fonctions d'extraction d'archive
+type Extractor func(ctx context.Context, scriptsPath, gamePath, archivePath string, callback InstallProgressCallback) ([]ExtractedFile, error)
+
+// ExtractorFor retourne la fonction d'extraction adaptée au format détecté
+func ExtractorFor(format ArchiveFormat) (Extractor, error) {
+	switch format {
+	case FormatZip:
+		return ExtractZip, nil
+	case FormatRar:
+		return ExtractRar, nil
+	case FormatSevenZip:
+		return ExtractSevenZip, nil
+	case FormatTar, FormatTarGz:
+		return ExtractTar, nil
+	case FormatPack:
+		return ExtractRawFile, nil
+	case FormatHTML:
+		return nil, fmt.Errorf("page HTML reçue au lieu d'une archive")
+	case FormatUnknown:
+		return nil, fmt.Errorf("format d'archive non reconnu")
+	default:
+		return nil, fmt.Errorf("format d'archive non supporté: %s", format)
+	}
+}
+
+// ExtractRawFile installe un fichier non archivé (un .pack brut par exemple) sous son
+// propre nom, avec la même sémantique que les autres extracteurs
+func ExtractRawFile(ctx context.Context, scriptsPath, gamePath, filePath string, callback InstallProgressCallback) ([]ExtractedFile, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+
+	name := filepath.Base(filePath)
+	if callback != nil {
+		callback(name, 0, 1)
+	}
+
+	destPath := ntw.GetDestinationPath(scriptsPath, gamePath, name)
+	extracted, err := ExtractFile(name, destPath, false, 0644, func() (io.ReadCloser, error) {
+		return os.Open(filePath)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("erreur copie %s: %w", name, err)
+	}
+	return []ExtractedFile{*extracted}, nil
+}
@@ -0,0 +1,61 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"mod-installer/utils/ntw"
+)
+
+// ExtractDirectory installe le contenu d'une arborescence déjà extraite (un dossier
+// Google Drive assemblé par exemple) avec la même sémantique que les extracteurs
+// d'archive : les chemins relatifs au dossier tiennent lieu de noms d'entrées.
+func ExtractDirectory(ctx context.Context, scriptsPath, gamePath, dirPath string, callback InstallProgressCallback) ([]ExtractedFile, error) {
+	names := make([]string, 0)
+	err := filepath.WalkDir(dirPath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.Type().IsRegular() {
+			rel, err := filepath.Rel(dirPath, path)
+			if err != nil {
+				return err
+			}
+			names = append(names, filepath.ToSlash(rel))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("erreur lecture du dossier %s: %w", dirPath, err)
+	}
+
+	written := make([]ExtractedFile, 0, len(names))
+	for i, name := range names {
+		select {
+		case <-ctx.Done():
+			return written, ctx.Err()
+		default:
+		}
+
+		if callback != nil {
+			callback(name, i, len(names))
+		}
+
+		srcPath := filepath.Join(dirPath, filepath.FromSlash(name))
+		destPath := ntw.GetDestinationPath(scriptsPath, gamePath, name)
+		extracted, err := ExtractFile(name, destPath, false, 0644, func() (io.ReadCloser, error) {
+			return os.Open(srcPath)
+		})
+		if err != nil {
+			return written, fmt.Errorf("erreur copie %s: %w", name, err)
+		}
+		if extracted != nil {
+			written = append(written, *extracted)
+		}
+	}
+	return written, nil
+}
@@ -0,0 +1,299 @@
+package utils
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/bodgit/sevenzip"
+	"github.com/nwaples/rardecode/v2"
+)
+
+// ArchiveEntry est un fichier contenu dans une archive
+type ArchiveEntry struct {
+	Name string // Chemin dans l'archive, tel que l'extracteur le reçoit
+	Size int64  // Taille décompressée
+}
+
+// ListArchiveEntries liste les fichiers d'une archive sans l'extraire : répertoire
+// central pour un ZIP, en-têtes pour un RAR ou un tar. Les dossiers ne sont pas listés.
+// Un dossier (téléchargement Google Drive assemblé) est parcouru comme une archive.
+func ListArchiveEntries(archivePath string) ([]ArchiveEntry, error) {
+	info, err := os.Stat(archivePath)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		return listDirectory(archivePath)
+	}
+
+	format, err := DetectArchiveFormat(archivePath)
+	if err != nil {
+		return nil, err
+	}
+	switch format {
+	case FormatZip:
+		return listZip(archivePath)
+	case FormatRar:
+		return listRar(archivePath)
+	case FormatSevenZip:
+		return listSevenZip(archivePath)
+	case FormatTar, FormatTarGz:
+		return listTar(archivePath, format)
+	case FormatPack:
+		return []ArchiveEntry{{Name: filepath.Base(archivePath), Size: info.Size()}}, nil
+	default:
+		return nil, fmt.Errorf("format d'archive non supporté: %s", format)
+	}
+}
+
+func listZip(archivePath string) ([]ArchiveEntry, error) {
+	reader, err := zip.OpenReader(archivePath)
+	if err != nil {
+		return nil, fmt.Errorf("erreur ouverture ZIP: %w", err)
+	}
+	defer reader.Close()
+
+	entries := make([]ArchiveEntry, 0, len(reader.File))
+	for _, file := range reader.File {
+		if !file.FileInfo().IsDir() {
+			entries = append(entries, ArchiveEntry{Name: file.Name, Size: int64(file.UncompressedSize64)})
+		}
+	}
+	return entries, nil
+}
+
+func listRar(archivePath string) ([]ArchiveEntry, error) {
+	files, err := rardecode.List(archivePath)
+	if err != nil {
+		return nil, fmt.Errorf("erreur lecture des en-têtes RAR: %w", err)
+	}
+
+	entries := make([]ArchiveEntry, 0, len(files))
+	for _, file := range files {
+		if !file.IsDir {
+			entries = append(entries, ArchiveEntry{Name: file.Name, Size: file.UnPackedSize})
+		}
+	}
+	return entries, nil
+}
+
+func listSevenZip(archivePath string) ([]ArchiveEntry, error) {
+	reader, err := sevenzip.OpenReader(archivePath)
+	if err != nil {
+		return nil, fmt.Errorf("erreur ouverture 7z: %w", err)
+	}
+	defer reader.Close()
+
+	entries := make([]ArchiveEntry, 0, len(reader.File))
+	for _, file := range reader.File {
+		if !file.FileInfo().IsDir() {
+			entries = append(entries, ArchiveEntry{Name: file.Name, Size: int64(file.UncompressedSize)})
+		}
+	}
+	return entries, nil
+}
+
+func listTar(archivePath string, format ArchiveFormat) ([]ArchiveEntry, error) {
+	file, err := os.Open(archivePath)
+	if err != nil {
+		return nil, fmt.Errorf("erreur ouverture TAR: %w", err)
+	}
+	defer file.Close()
+
+	var src io.Reader = file
+	if format == FormatTarGz {
+		gz, err := gzip.NewReader(file)
+		if err != nil {
+			return nil, fmt.Errorf("erreur décompression gzip: %w", err)
+		}
+		defer gz.Close()
+		src = gz
+	}
+
+	reader := tar.NewReader(src)
+	entries := make([]ArchiveEntry, 0)
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			return entries, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("erreur lecture header TAR: %w", err)
+		}
+		// Seuls les fichiers ordinaires sont extraits
+		if header.Typeflag == tar.TypeReg {
+			entries = append(entries, ArchiveEntry{Name: header.Name, Size: header.Size})
+		}
+	}
+}
+
+func listDirectory(dirPath string) ([]ArchiveEntry, error) {
+	entries := make([]ArchiveEntry, 0)
+	err := filepath.WalkDir(dirPath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || !d.Type().IsRegular() {
+			return err
+		}
+		rel, err := filepath.Rel(dirPath, path)
+		if err != nil {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		entries = append(entries, ArchiveEntry{Name: filepath.ToSlash(rel), Size: info.Size()})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("erreur lecture du dossier %s: %w", dirPath, err)
+	}
+	return entries, nil
+}
+
+// ReadArchiveEntries lit sans extraction les fichiers de l'archive retenus par match :
+// visit reçoit chaque entrée avec un flux de son contenu, valable pendant l'appel
+func ReadArchiveEntries(archivePath string, match func(name string) bool, visit func(entry ArchiveEntry, r io.Reader) error) error {
+	info, err := os.Stat(archivePath)
+	if err != nil {
+		return err
+	}
+	if info.IsDir() {
+		entries, err := listDirectory(archivePath)
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			if match(entry.Name) {
+				if err := visitFile(filepath.Join(archivePath, filepath.FromSlash(entry.Name)), entry, visit); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+
+	format, err := DetectArchiveFormat(archivePath)
+	if err != nil {
+		return err
+	}
+	switch format {
+	case FormatZip:
+		reader, err := zip.OpenReader(archivePath)
+		if err != nil {
+			return fmt.Errorf("erreur ouverture ZIP: %w", err)
+		}
+		defer reader.Close()
+		for _, file := range reader.File {
+			if file.FileInfo().IsDir() || !match(file.Name) {
+				continue
+			}
+			if err := visitOpened(ArchiveEntry{Name: file.Name, Size: int64(file.UncompressedSize64)}, file.Open, visit); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case FormatSevenZip:
+		reader, err := sevenzip.OpenReader(archivePath)
+		if err != nil {
+			return fmt.Errorf("erreur ouverture 7z: %w", err)
+		}
+		defer reader.Close()
+		for _, file := range reader.File {
+			if file.FileInfo().IsDir() || !match(file.Name) {
+				continue
+			}
+			if err := visitOpened(ArchiveEntry{Name: file.Name, Size: int64(file.UncompressedSize)}, file.Open, visit); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case FormatRar:
+		file, err := os.Open(archivePath)
+		if err != nil {
+			return fmt.Errorf("erreur ouverture RAR: %w", err)
+		}
+		defer file.Close()
+		reader, err := rardecode.NewReader(file)
+		if err != nil {
+			return fmt.Errorf("erreur création lecteur RAR: %w", err)
+		}
+		for {
+			header, err := reader.Next()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return fmt.Errorf("erreur lecture header RAR: %w", err)
+			}
+			if header.IsDir || !match(header.Name) {
+				continue
+			}
+			if err := visit(ArchiveEntry{Name: header.Name, Size: header.UnPackedSize}, reader); err != nil {
+				return err
+			}
+		}
+
+	case FormatTar, FormatTarGz:
+		file, err := os.Open(archivePath)
+		if err != nil {
+			return fmt.Errorf("erreur ouverture TAR: %w", err)
+		}
+		defer file.Close()
+		var src io.Reader = file
+		if format == FormatTarGz {
+			gz, err := gzip.NewReader(file)
+			if err != nil {
+				return fmt.Errorf("erreur décompression gzip: %w", err)
+			}
+			defer gz.Close()
+			src = gz
+		}
+		reader := tar.NewReader(src)
+		for {
+			header, err := reader.Next()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return fmt.Errorf("erreur lecture header TAR: %w", err)
+			}
+			if header.Typeflag != tar.TypeReg || !match(header.Name) {
+				continue
+			}
+			if err := visit(ArchiveEntry{Name: header.Name, Size: header.Size}, reader); err != nil {
+				return err
+			}
+		}
+
+	case FormatPack:
+		entry := ArchiveEntry{Name: filepath.Base(archivePath), Size: info.Size()}
+		if !match(entry.Name) {
+			return nil
+		}
+		return visitFile(archivePath, entry, visit)
+
+	default:
+		return fmt.Errorf("format d'archive non supporté: %s", format)
+	}
+}
+
+func visitFile(path string, entry ArchiveEntry, visit func(ArchiveEntry, io.Reader) error) error {
+	return visitOpened(entry, func() (io.ReadCloser, error) { return os.Open(path) }, visit)
+}
+
+func visitOpened(entry ArchiveEntry, open func() (io.ReadCloser, error), visit func(ArchiveEntry, io.Reader) error) error {
+	r, err := open()
+	if err != nil {
+		return fmt.Errorf("erreur ouverture de %s: %w", entry.Name, err)
+	}
+	defer r.Close()
+	return visit(entry, r)
+}
@@ -8,36 +8,47 @@ import (
 	"strings"
 )
 
-func ExtractFile(name, destPath string, isDir bool, mode os.FileMode, opener func() (io.ReadCloser, error)) error {
+// ExtractFile écrit une entrée d'archive dans destPath et retourne le fichier écrit
+// (nil pour un dossier)
+func ExtractFile(name, destPath string, isDir bool, mode os.FileMode, opener func() (io.ReadCloser, error)) (*ExtractedFile, error) {
 	destFile := filepath.Join(destPath, name)
 	
 	// Vérification de sécurité contre les path traversal
 	if !strings.HasPrefix(destFile, filepath.Clean(destPath)+string(os.PathSeparator)) {
-		return fmt.Errorf("chemin invalide: %s", name)
+		return nil, fmt.Errorf("chemin invalide: %s", name)
 	}
 
 	if err := os.MkdirAll(filepath.Dir(destFile), 0755); err != nil {
-		return err
+		return nil, err
 	}
 
 	if isDir {
-		return os.MkdirAll(destFile, mode)
+		return nil, os.MkdirAll(destFile, mode)
 	}
 
 	rc, err := opener()
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer rc.Close()
 
+	extracted := &ExtractedFile{
+		Name:     name,
+		DestRoot: filepath.Clean(destPath),
+		Path:     destFile,
+		Replaced: FileExists(destFile),
+	}
+
 	outFile, err := os.OpenFile(destFile, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer outFile.Close()
 
-	_, err = io.Copy(outFile, rc)
-	return err
+	if _, err := io.Copy(outFile, rc); err != nil {
+		return nil, err
+	}
+	return extracted, nil
 }
 
 // CopyFile copie un fichier de src vers dst
@@ -119,6 +130,30 @@ func SafeFileMove(src, dst string) error {
 	return nil
 }
 
+// MoveFile déplace src vers dst en remplaçant dst de manière atomique lorsque c'est
+// possible. Entre deux systèmes de fichiers, le contenu est copié dans un fichier
+// temporaire voisin de dst puis renommé.
+func MoveFile(src, dst string) error {
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return fmt.Errorf("impossible de créer le répertoire de destination: %v", err)
+	}
+
+	if err := os.Rename(src, dst); err == nil {
+		return nil
+	}
+
+	tmpPath := dst + ".modinstaller-tmp"
+	if err := CopyFile(src, tmpPath); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := os.Rename(tmpPath, dst); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("impossible de remplacer %s: %v", dst, err)
+	}
+	return os.Remove(src)
+}
+
 // GetRelativePath retourne le chemin relatif d'un fichier par rapport à un répertoire de base
 func GetRelativePath(basePath, filePath string) (string, error) {
 	rel, err := filepath.Rel(basePath, filePath)
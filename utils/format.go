@@ -0,0 +1,129 @@
+package utils
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+)
+
+// ArchiveFormat est le format d'un fichier téléchargé, détecté d'après son contenu
+type ArchiveFormat string
+
+const (
+	FormatUnknown  ArchiveFormat = ""
+	FormatZip      ArchiveFormat = "zip"
+	FormatRar      ArchiveFormat = "rar"
+	FormatSevenZip ArchiveFormat = "7z"
+	FormatTar      ArchiveFormat = "tar"
+	FormatTarGz    ArchiveFormat = "tar.gz"
+	FormatGzip     ArchiveFormat = "gz"
+	FormatPack     ArchiveFormat = "pack" // Fichier .pack NTW brut, sans archive
+	FormatHTML     ArchiveFormat = "html" // Page web reçue à la place du fichier
+)
+
+// Extension retourne l'extension de fichier associée au format (avec le point)
+func (f ArchiveFormat) Extension() string {
+	if f == FormatUnknown {
+		return ""
+	}
+	return "." + string(f)
+}
+
+// IsArchive indique si le format est une archive que l'installeur sait extraire
+func (f ArchiveFormat) IsArchive() bool {
+	switch f {
+	case FormatZip, FormatRar, FormatSevenZip, FormatTar, FormatTarGz:
+		return true
+	}
+	return false
+}
+
+var (
+	magicZip      = []byte("PK\x03\x04")
+	magicZipEmpty = []byte("PK\x05\x06")
+	magicZipSpan  = []byte("PK\x07\x08")
+	magicRar4     = []byte("Rar!\x1a\x07\x00")
+	magicRar5     = []byte("Rar!\x1a\x07\x01\x00")
+	magicSevenZip = []byte("7z\xbc\xaf\x27\x1c")
+	magicGzip     = []byte("\x1f\x8b")
+	magicPack     = []byte("PFH")
+	magicTar      = []byte("ustar")
+)
+
+// sniffLength est le nombre d'octets nécessaires à DetectFormat
+const sniffLength = 512
+
+// DetectFormat identifie un format d'après les premiers octets d'un fichier
+func DetectFormat(head []byte) ArchiveFormat {
+	switch {
+	case bytes.HasPrefix(head, magicZip), bytes.HasPrefix(head, magicZipEmpty), bytes.HasPrefix(head, magicZipSpan):
+		return FormatZip
+	case bytes.HasPrefix(head, magicRar4), bytes.HasPrefix(head, magicRar5):
+		return FormatRar
+	case bytes.HasPrefix(head, magicSevenZip):
+		return FormatSevenZip
+	case bytes.HasPrefix(head, magicGzip):
+		return detectGzipContent(head)
+	case len(head) >= 4 && bytes.HasPrefix(head, magicPack) && head[3] >= '0' && head[3] <= '9':
+		return FormatPack
+	case isTarHeader(head):
+		return FormatTar
+	case looksLikeHTML(head):
+		return FormatHTML
+	}
+	return FormatUnknown
+}
+
+// DetectArchiveFormat lit le début du fichier et retourne son format
+func DetectArchiveFormat(path string) (ArchiveFormat, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return FormatUnknown, fmt.Errorf("impossible d'ouvrir %s: %w", path, err)
+	}
+	defer file.Close()
+
+	head := make([]byte, sniffLength)
+	n, err := io.ReadFull(file, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return FormatUnknown, err
+	}
+	format := DetectFormat(head[:n])
+
+	// Une archive gzip dont l'en-tête tar est au-delà du premier bloc lu
+	if format == FormatGzip {
+		if _, err := file.Seek(0, io.SeekStart); err == nil {
+			format = detectGzipStream(file)
+		}
+	}
+	return format, nil
+}
+
+func isTarHeader(head []byte) bool {
+	return len(head) >= 262 && bytes.HasPrefix(head[257:], magicTar)
+}
+
+func looksLikeHTML(head []byte) bool {
+	trimmed := bytes.ToLower(bytes.TrimSpace(head))
+	return bytes.HasPrefix(trimmed, []byte("<!doctype html")) || bytes.HasPrefix(trimmed, []byte("<html"))
+}
+
+func detectGzipContent(head []byte) ArchiveFormat {
+	return detectGzipStream(bytes.NewReader(head))
+}
+
+func detectGzipStream(r io.Reader) ArchiveFormat {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return FormatGzip
+	}
+	defer gz.Close()
+
+	inner := make([]byte, sniffLength)
+	n, _ := io.ReadFull(gz, inner)
+	if isTarHeader(inner[:n]) {
+		return FormatTarGz
+	}
+	return FormatGzip
+}
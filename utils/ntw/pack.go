@@ -0,0 +1,269 @@
+package ntw
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// PackType est le rôle d'un fichier .pack dans le chargement du jeu
+type PackType uint32
+
+const (
+	PackBoot    PackType = iota // Chargé au démarrage (boot.pack)
+	PackRelease                 // Contenu du jeu livré (data.pack, units.pack...)
+	PackPatch                   // Correctifs officiels (patch.pack...)
+	PackMod                     // Mod : chargé en dernier, écrase les autres
+	PackMovie                   // Vidéos et sons, chargé même sans être déclaré
+)
+
+func (t PackType) String() string {
+	switch t {
+	case PackBoot:
+		return "boot"
+	case PackRelease:
+		return "release"
+	case PackPatch:
+		return "patch"
+	case PackMod:
+		return "mod"
+	case PackMovie:
+		return "movie"
+	default:
+		return fmt.Sprintf("type %d", uint32(t))
+	}
+}
+
+// Indicateurs du champ type de l'en-tête
+const (
+	packTypeMask           = 0x0F
+	packFlagIndexTimestamp = 0x40  // Chaque entrée de l'index porte une date
+	packFlagEncryptedIndex = 0x80  // Index chiffré (Arena, Warhammer)
+	packFlagExtendedHeader = 0x100 // En-tête étendu (PFH5)
+)
+
+// Limite de taille des index, pour refuser un en-tête corrompu avant d'allouer
+const maxPackIndexSize = 64 << 20
+
+// PackFile est une entrée de l'index d'un pack
+type PackFile struct {
+	Path      string // Chemin interne, séparateurs "/" (db/units_tables/units)
+	Size      int64
+	Offset    int64 // Position des données dans le fichier .pack
+	Timestamp time.Time
+}
+
+// Pack est l'en-tête et l'index d'un fichier .pack (PFH0 à PFH4)
+type Pack struct {
+	Version      string // PFH0 (Empire, Napoleon), PFH2 et PFH3 (Shogun 2), PFH4 (Rome 2)
+	Type         PackType
+	Flags        uint32 // Bits du champ type au-delà du type lui-même
+	Timestamp    time.Time
+	Dependencies []string // Packs requis, dans l'ordre de l'en-tête
+	Files        []PackFile
+}
+
+// ReadPackFile lit l'en-tête et l'index d'un fichier .pack, sans lire les données
+func ReadPackFile(path string) (*Pack, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	pack, err := ReadPack(bufio.NewReader(file))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return pack, nil
+}
+
+// ReadPack lit l'en-tête et l'index d'un pack depuis r, positionné au début du pack
+func ReadPack(r io.Reader) (*Pack, error) {
+	var magic [4]byte
+	if _, err := io.ReadFull(r, magic[:]); err != nil {
+		return nil, fmt.Errorf("en-tête de pack illisible: %w", err)
+	}
+	pack := &Pack{Version: string(magic[:])}
+
+	switch pack.Version {
+	case "PFH0", "PFH2", "PFH3", "PFH4":
+	default:
+		return nil, fmt.Errorf("format de pack non supporté: %q", pack.Version)
+	}
+
+	var header struct {
+		Type          uint32
+		PackCount     uint32
+		PackIndexSize uint32
+		FileCount     uint32
+		FileIndexSize uint32
+	}
+	if err := binary.Read(r, binary.LittleEndian, &header); err != nil {
+		return nil, fmt.Errorf("en-tête de pack tronqué: %w", err)
+	}
+	pack.Type = PackType(header.Type & packTypeMask)
+	pack.Flags = header.Type &^ packTypeMask
+	if pack.Flags&packFlagEncryptedIndex != 0 {
+		return nil, fmt.Errorf("index de pack chiffré non supporté")
+	}
+	if pack.Flags&packFlagExtendedHeader != 0 {
+		return nil, fmt.Errorf("en-tête de pack étendu non supporté")
+	}
+	if header.PackIndexSize > maxPackIndexSize || header.FileIndexSize > maxPackIndexSize {
+		return nil, fmt.Errorf("index de pack trop grand (%d + %d octets)", header.PackIndexSize, header.FileIndexSize)
+	}
+
+	// Date du pack : FILETIME Windows en PFH2/PFH3, secondes Unix en PFH4, aucune en PFH0
+	headerSize := int64(4 + 20)
+	timestamps := pack.Flags&packFlagIndexTimestamp != 0
+	switch pack.Version {
+	case "PFH2", "PFH3":
+		var filetime uint64
+		if err := binary.Read(r, binary.LittleEndian, &filetime); err != nil {
+			return nil, fmt.Errorf("en-tête de pack tronqué: %w", err)
+		}
+		pack.Timestamp = fromFileTime(filetime)
+		headerSize += 8
+	case "PFH4":
+		var seconds uint32
+		if err := binary.Read(r, binary.LittleEndian, &seconds); err != nil {
+			return nil, fmt.Errorf("en-tête de pack tronqué: %w", err)
+		}
+		pack.Timestamp = fromUnix(uint64(seconds))
+		headerSize += 4
+	}
+
+	packIndex := make([]byte, header.PackIndexSize)
+	if _, err := io.ReadFull(r, packIndex); err != nil {
+		return nil, fmt.Errorf("liste des dépendances tronquée: %w", err)
+	}
+	names, rest, err := readStrings(packIndex, int(header.PackCount))
+	if err != nil {
+		return nil, fmt.Errorf("liste des dépendances: %w", err)
+	}
+	if len(rest) != 0 {
+		return nil, fmt.Errorf("liste des dépendances: %d octet(s) en trop", len(rest))
+	}
+	pack.Dependencies = names
+
+	fileIndex := make([]byte, header.FileIndexSize)
+	if _, err := io.ReadFull(r, fileIndex); err != nil {
+		return nil, fmt.Errorf("index des fichiers tronqué: %w", err)
+	}
+	pack.Files, err = parseFileIndex(pack.Version, fileIndex, int(header.FileCount), timestamps)
+	if err != nil {
+		return nil, fmt.Errorf("index des fichiers: %w", err)
+	}
+
+	// Les données suivent l'index, dans l'ordre des entrées
+	offset := headerSize + int64(header.PackIndexSize) + int64(header.FileIndexSize)
+	for i := range pack.Files {
+		pack.Files[i].Offset = offset
+		offset += pack.Files[i].Size
+	}
+	return pack, nil
+}
+
+// Find retourne l'entrée de chemin interne path (casse et séparateurs ignorés)
+func (p *Pack) Find(path string) *PackFile {
+	key := PackPathKey(path)
+	for i := range p.Files {
+		if PackPathKey(p.Files[i].Path) == key {
+			return &p.Files[i]
+		}
+	}
+	return nil
+}
+
+// DataSize retourne la taille totale des fichiers contenus
+func (p *Pack) DataSize() int64 {
+	var total int64
+	for _, file := range p.Files {
+		total += file.Size
+	}
+	return total
+}
+
+// PackPathKey normalise un chemin interne : le jeu ignore la casse et accepte "\" et "/"
+func PackPathKey(path string) string {
+	return strings.ToLower(strings.ReplaceAll(path, "\\", "/"))
+}
+
+func parseFileIndex(version string, data []byte, count int, timestamps bool) ([]PackFile, error) {
+	files := make([]PackFile, 0, min(count, len(data)/5))
+	for i := 0; i < count; i++ {
+		if len(data) < 4 {
+			return nil, fmt.Errorf("entrée %d tronquée", i)
+		}
+		file := PackFile{Size: int64(binary.LittleEndian.Uint32(data))}
+		data = data[4:]
+
+		if timestamps {
+			switch version {
+			case "PFH2", "PFH3":
+				if len(data) < 8 {
+					return nil, fmt.Errorf("entrée %d tronquée", i)
+				}
+				file.Timestamp = fromFileTime(binary.LittleEndian.Uint64(data))
+				data = data[8:]
+			default:
+				if len(data) < 4 {
+					return nil, fmt.Errorf("entrée %d tronquée", i)
+				}
+				file.Timestamp = fromUnix(uint64(binary.LittleEndian.Uint32(data)))
+				data = data[4:]
+			}
+		}
+
+		end := bytes.IndexByte(data, 0)
+		if end < 0 {
+			return nil, fmt.Errorf("chemin de l'entrée %d non terminé", i)
+		}
+		file.Path = strings.ReplaceAll(string(data[:end]), "\\", "/")
+		data = data[end+1:]
+		files = append(files, file)
+	}
+	if len(data) != 0 {
+		return nil, fmt.Errorf("%d octet(s) en trop après %d entrées", len(data), count)
+	}
+	return files, nil
+}
+
+// readStrings lit count chaînes terminées par un octet nul
+func readStrings(data []byte, count int) ([]string, []byte, error) {
+	values := make([]string, 0, min(count, len(data)))
+	for i := 0; i < count; i++ {
+		end := bytes.IndexByte(data, 0)
+		if end < 0 {
+			return nil, nil, fmt.Errorf("chaîne %d non terminée", i)
+		}
+		values = append(values, string(data[:end]))
+		data = data[end+1:]
+	}
+	return values, data, nil
+}
+
+// fromFileTime convertit un FILETIME Windows (centaines de ns depuis 1601)
+func fromFileTime(filetime uint64) time.Time {
+	if filetime == 0 {
+		return time.Time{}
+	}
+	const unixEpoch = 116444736000000000
+	if filetime < unixEpoch {
+		return time.Time{}
+	}
+	return time.Unix(0, int64(filetime-unixEpoch)*100).UTC()
+}
+
+func fromUnix(seconds uint64) time.Time {
+	if seconds == 0 {
+		return time.Time{}
+	}
+	return time.Unix(int64(seconds), 0).UTC()
+}
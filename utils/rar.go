@@ -16,23 +16,25 @@ import (
 // Déplacé ici pour éviter l'import cyclique
 type InstallProgressCallback func(currentFile string, processed, total int)
 
-func ExtractRar(ctx context.Context, scriptsPath, gamePath, archivePath string, callback InstallProgressCallback) error {
+// ExtractRar extrait une archive RAR et retourne la liste des fichiers écrits
+func ExtractRar(ctx context.Context, scriptsPath, gamePath, archivePath string, callback InstallProgressCallback) ([]ExtractedFile, error) {
 	file, err := os.Open(archivePath)
 	if err != nil {
-		return fmt.Errorf("erreur ouverture RAR: %w", err)
+		return nil, fmt.Errorf("erreur ouverture RAR: %w", err)
 	}
 	defer file.Close()
 
 	reader, err := rardecode.NewReader(file)
 	if err != nil {
-		return fmt.Errorf("erreur création lecteur RAR: %w", err)
+		return nil, fmt.Errorf("erreur création lecteur RAR: %w", err)
 	}
 
+	written := make([]ExtractedFile, 0)
 	processed := 0
 	for {
 		select {
 		case <-ctx.Done():
-			return ctx.Err()
+			return written, ctx.Err()
 		default:
 		}
 
@@ -41,7 +43,7 @@ func ExtractRar(ctx context.Context, scriptsPath, gamePath, archivePath string,
 			break
 		}
 		if err != nil {
-			return fmt.Errorf("erreur lecture header RAR: %w", err)
+			return written, fmt.Errorf("erreur lecture header RAR: %w", err)
 		}
 
 		if callback != nil {
@@ -51,10 +53,14 @@ func ExtractRar(ctx context.Context, scriptsPath, gamePath, archivePath string,
 		// Déterminer le dossier de destination basé sur l'extension
 		destPath := ntw.GetDestinationPath(scriptsPath, gamePath, header.Name)
 
-		if err := ExtractFile(header.Name, destPath, header.IsDir, 0644, func() (io.ReadCloser, error) {
+		extracted, err := ExtractFile(header.Name, destPath, header.IsDir, 0644, func() (io.ReadCloser, error) {
 			return io.NopCloser(reader), nil
-		}); err != nil {
-			return fmt.Errorf("erreur extraction %s: %w", header.Name, err)
+		})
+		if err != nil {
+			return written, fmt.Errorf("erreur extraction %s: %w", header.Name, err)
+		}
+		if extracted != nil {
+			written = append(written, *extracted)
 		}
 
 		if !header.ModificationTime.IsZero() {
@@ -63,5 +69,5 @@ func ExtractRar(ctx context.Context, scriptsPath, gamePath, archivePath string,
 		}
 		processed++
 	}
-	return nil
+	return written, nil
 }
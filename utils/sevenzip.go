@@ -0,0 +1,56 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/bodgit/sevenzip"
+
+	"mod-installer/utils/ntw"
+)
+
+// ExtractSevenZip extrait une archive 7z et retourne la liste des fichiers écrits.
+// Les entrées sont lues dans l'ordre de l'archive, ce qui évite de redécompresser
+// les blocs solides.
+func ExtractSevenZip(ctx context.Context, scriptsPath, gamePath, archivePath string, callback InstallProgressCallback) ([]ExtractedFile, error) {
+	reader, err := sevenzip.OpenReader(archivePath)
+	if err != nil {
+		return nil, fmt.Errorf("erreur ouverture 7z: %w", err)
+	}
+	defer reader.Close()
+
+	written := make([]ExtractedFile, 0, len(reader.File))
+	for i, file := range reader.File {
+		select {
+		case <-ctx.Done():
+			return written, ctx.Err()
+		default:
+		}
+
+		if callback != nil {
+			callback(file.Name, i, len(reader.File))
+		}
+
+		// Déterminer le dossier de destination basé sur l'extension
+		destPath := ntw.GetDestinationPath(scriptsPath, gamePath, file.Name)
+
+		extracted, err := ExtractFile(file.Name, destPath, file.FileInfo().IsDir(), 0644, func() (io.ReadCloser, error) {
+			return file.Open()
+		})
+		if err != nil {
+			return written, fmt.Errorf("erreur extraction %s: %w", file.Name, err)
+		}
+		if extracted != nil {
+			written = append(written, *extracted)
+		}
+
+		if !file.Modified.IsZero() && !file.FileInfo().IsDir() {
+			destFile := filepath.Join(destPath, file.Name)
+			os.Chtimes(destFile, file.Modified, file.Modified)
+		}
+	}
+	return written, nil
+}
@@ -0,0 +1,87 @@
+package utils
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"mod-installer/utils/ntw"
+)
+
+// ExtractTar extrait une archive tar, éventuellement compressée en gzip, et retourne
+// la liste des fichiers écrits. Les liens symboliques sont ignorés.
+func ExtractTar(ctx context.Context, scriptsPath, gamePath, archivePath string, callback InstallProgressCallback) ([]ExtractedFile, error) {
+	file, err := os.Open(archivePath)
+	if err != nil {
+		return nil, fmt.Errorf("erreur ouverture TAR: %w", err)
+	}
+	defer file.Close()
+
+	format, err := DetectArchiveFormat(archivePath)
+	if err != nil {
+		return nil, err
+	}
+
+	var src io.Reader = file
+	if format == FormatTarGz {
+		gz, err := gzip.NewReader(file)
+		if err != nil {
+			return nil, fmt.Errorf("erreur décompression gzip: %w", err)
+		}
+		defer gz.Close()
+		src = gz
+	}
+
+	reader := tar.NewReader(src)
+	written := make([]ExtractedFile, 0)
+	processed := 0
+	for {
+		select {
+		case <-ctx.Done():
+			return written, ctx.Err()
+		default:
+		}
+
+		header, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return written, fmt.Errorf("erreur lecture header TAR: %w", err)
+		}
+
+		isDir := header.Typeflag == tar.TypeDir
+		if !isDir && header.Typeflag != tar.TypeReg {
+			fmt.Printf("Entrée TAR ignorée (type %c): %s\n", header.Typeflag, header.Name)
+			continue
+		}
+
+		if callback != nil {
+			callback(header.Name, processed, 0)
+		}
+
+		// Déterminer le dossier de destination basé sur l'extension
+		destPath := ntw.GetDestinationPath(scriptsPath, gamePath, header.Name)
+
+		extracted, err := ExtractFile(header.Name, destPath, isDir, 0644, func() (io.ReadCloser, error) {
+			return io.NopCloser(reader), nil
+		})
+		if err != nil {
+			return written, fmt.Errorf("erreur extraction %s: %w", header.Name, err)
+		}
+		if extracted != nil {
+			written = append(written, *extracted)
+		}
+
+		if !isDir && !header.ModTime.IsZero() {
+			destFile := filepath.Join(destPath, header.Name)
+			os.Chtimes(destFile, header.ModTime, header.ModTime)
+		}
+		processed++
+	}
+	return written, nil
+}
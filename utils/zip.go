@@ -10,17 +10,19 @@ import (
 )
 
 
-func ExtractZip(ctx context.Context, scriptsPath, gamePath, archivePath string, callback InstallProgressCallback) error {
+// ExtractZip extrait une archive ZIP et retourne la liste des fichiers écrits
+func ExtractZip(ctx context.Context, scriptsPath, gamePath, archivePath string, callback InstallProgressCallback) ([]ExtractedFile, error) {
 	reader, err := zip.OpenReader(archivePath)
 	if err != nil {
-		return fmt.Errorf("erreur ouverture ZIP: %w", err)
+		return nil, fmt.Errorf("erreur ouverture ZIP: %w", err)
 	}
 	defer reader.Close()
 
+	written := make([]ExtractedFile, 0, len(reader.File))
 	for i, file := range reader.File {
 		select {
 		case <-ctx.Done():
-			return ctx.Err()
+			return written, ctx.Err()
 		default:
 		}
 
@@ -31,11 +33,15 @@ func ExtractZip(ctx context.Context, scriptsPath, gamePath, archivePath string,
 		// Déterminer le dossier de destination basé sur l'extension
 		destPath := ntw.GetDestinationPath(scriptsPath, gamePath, file.Name)
 
-		if err := ExtractFile(file.Name, destPath, file.FileInfo().IsDir(), file.FileInfo().Mode(), func() (io.ReadCloser, error) {
+		extracted, err := ExtractFile(file.Name, destPath, file.FileInfo().IsDir(), file.FileInfo().Mode(), func() (io.ReadCloser, error) {
 			return file.Open()
-		}); err != nil {
-			return fmt.Errorf("erreur extraction %s: %w", file.Name, err)
+		})
+		if err != nil {
+			return written, fmt.Errorf("erreur extraction %s: %w", file.Name, err)
+		}
+		if extracted != nil {
+			written = append(written, *extracted)
 		}
 	}
-	return nil
+	return written, nil
 }